@@ -44,16 +44,58 @@ func (z *Sink) Write(level hc.Level, message string, fields map[string]any) {
 		zapFields = append(zapFields, zap.Any(k, v))
 	}
 
+	z.log(level, message, zapFields)
+}
+
+// WriteFields implements hc.FieldSink, writing typed fields without boxing.
+func (z *Sink) WriteFields(level hc.Level, message string, fields []hc.Field) {
+	if z == nil || z.logger == nil {
+		return
+	}
+	if message == "" {
+		message = "request_completed"
+	}
+
+	bufPtr := zapFieldPool.Get().(*[]zap.Field)
+	zapFields := (*bufPtr)[:0]
+	defer func() {
+		*bufPtr = zapFields[:0]
+		zapFieldPool.Put(bufPtr)
+	}()
+
+	for _, f := range fields {
+		switch f.Kind() {
+		case hc.FieldKindString:
+			zapFields = append(zapFields, zap.String(f.Key, f.StringValue()))
+		case hc.FieldKindInt, hc.FieldKindInt64:
+			zapFields = append(zapFields, zap.Int64(f.Key, f.Int64Value()))
+		case hc.FieldKindFloat64:
+			zapFields = append(zapFields, zap.Float64(f.Key, f.Float64Value()))
+		case hc.FieldKindBool:
+			zapFields = append(zapFields, zap.Bool(f.Key, f.BoolValue()))
+		case hc.FieldKindDuration:
+			zapFields = append(zapFields, zap.Duration(f.Key, f.DurationValue()))
+		case hc.FieldKindTime:
+			zapFields = append(zapFields, zap.Time(f.Key, f.TimeValue()))
+		default:
+			zapFields = append(zapFields, zap.Any(f.Key, f.Value()))
+		}
+	}
+
+	z.log(level, message, zapFields)
+}
+
+func (z *Sink) log(level hc.Level, message string, fields []zap.Field) {
 	switch level {
 	case hc.LevelDebug:
-		z.logger.Debug(message, zapFields...)
+		z.logger.Debug(message, fields...)
 	case hc.LevelWarn:
-		z.logger.Warn(message, zapFields...)
+		z.logger.Warn(message, fields...)
 	case hc.LevelError:
-		z.logger.Error(message, zapFields...)
+		z.logger.Error(message, fields...)
 	default:
-		z.logger.Info(message, zapFields...)
+		z.logger.Info(message, fields...)
 	}
 }
 
-var _ hc.Sink = (*Sink)(nil)
+var _ hc.FieldSink = (*Sink)(nil)
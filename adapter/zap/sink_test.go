@@ -2,6 +2,7 @@ package zapadapter
 
 import (
 	"testing"
+	"time"
 
 	"github.com/happytoolin/happycontext"
 	"go.uber.org/zap"
@@ -80,3 +81,49 @@ func TestSinkWriteNilSafety(t *testing.T) {
 	sink := New(nil)
 	sink.Write(hc.LevelInfo, "x", map[string]any{"k": 1})
 }
+
+func TestSinkWriteFieldsKeepsTypes(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	sink := New(zap.New(core))
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	sink.WriteFields(hc.LevelWarn, "", []hc.Field{
+		hc.String("user_id", "u_1"),
+		hc.Int("http.status", 429),
+		hc.Float64("ratio", 0.5),
+		hc.Bool("retry", true),
+		hc.Duration("wait", 2*time.Second),
+		hc.Time("at", now),
+		hc.Any("tags", []string{"a"}),
+	})
+
+	if logs.Len() != 1 {
+		t.Fatalf("expected one log entry, got %d", logs.Len())
+	}
+	entry := logs.All()[0]
+	if entry.Level != zapcore.WarnLevel {
+		t.Fatalf("expected warn level, got %v", entry.Level)
+	}
+	if entry.Message != "request_completed" {
+		t.Fatalf("expected default message, got %q", entry.Message)
+	}
+	got := entry.ContextMap()
+	if got["user_id"] != "u_1" || got["http.status"] != int64(429) || got["ratio"] != 0.5 || got["retry"] != true {
+		t.Fatalf("unexpected scalar fields: %v", got)
+	}
+	if got["wait"] != 2*time.Second {
+		t.Fatalf("expected duration field, got %v", got["wait"])
+	}
+	if at, ok := got["at"].(time.Time); !ok || !at.Equal(now) {
+		t.Fatalf("expected time field, got %v", got["at"])
+	}
+	if tags, ok := got["tags"].([]any); !ok || len(tags) != 1 {
+		t.Fatalf("expected tags field, got %#v", got["tags"])
+	}
+}
+
+func TestSinkWriteFieldsNilSafe(t *testing.T) {
+	var sink *Sink
+	sink.WriteFields(hc.LevelInfo, "x", []hc.Field{hc.String("k", "v")})
+	New(nil).WriteFields(hc.LevelInfo, "x", nil)
+}
@@ -18,6 +18,15 @@ var benchFieldsSmall = map[string]any{
 	"plan":        "pro",
 }
 
+var benchFieldListSmall = []hc.Field{
+	hc.String("http.method", "GET"),
+	hc.String("http.path", "/orders/123"),
+	hc.Int("http.status", 204),
+	hc.Int("duration_ms", 7),
+	hc.String("user_id", "u_1"),
+	hc.String("plan", "pro"),
+}
+
 func benchFieldsMedium() map[string]any {
 	m := make(map[string]any, 15)
 	for i := 0; i < 15; i++ {
@@ -46,4 +55,11 @@ func BenchmarkAdapter_zerolog(b *testing.B) {
 			sink.Write(hc.LevelInfo, "request_completed", medium)
 		}
 	})
+
+	b.Run("write_fields_small", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sink.WriteFields(hc.LevelInfo, "request_completed", benchFieldListSmall)
+		}
+	})
 }
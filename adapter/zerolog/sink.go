@@ -26,57 +26,96 @@ func (z *Sink) Write(level hc.Level, message string, fields map[string]any) {
 		message = "request_completed"
 	}
 
-	event := z.logger.Info()
+	event := z.event(level)
+	for k, v := range fields {
+		event = appendValue(event, k, v)
+	}
+	event.Msg(message)
+}
+
+// WriteFields implements hc.FieldSink, writing typed fields without boxing.
+func (z *Sink) WriteFields(level hc.Level, message string, fields []hc.Field) {
+	if z == nil || z.logger == nil {
+		return
+	}
+	if message == "" {
+		message = "request_completed"
+	}
+
+	event := z.event(level)
+	for _, f := range fields {
+		switch f.Kind() {
+		case hc.FieldKindString:
+			event = event.Str(f.Key, f.StringValue())
+		case hc.FieldKindInt, hc.FieldKindInt64:
+			event = event.Int64(f.Key, f.Int64Value())
+		case hc.FieldKindFloat64:
+			event = event.Float64(f.Key, f.Float64Value())
+		case hc.FieldKindBool:
+			event = event.Bool(f.Key, f.BoolValue())
+		case hc.FieldKindDuration:
+			event = event.Dur(f.Key, f.DurationValue())
+		case hc.FieldKindTime:
+			event = event.Time(f.Key, f.TimeValue())
+		default:
+			event = appendValue(event, f.Key, f.Value())
+		}
+	}
+	event.Msg(message)
+}
+
+func (z *Sink) event(level hc.Level) *zerolog.Event {
 	switch level {
 	case hc.LevelDebug:
-		event = z.logger.Debug()
+		return z.logger.Debug()
 	case hc.LevelWarn:
-		event = z.logger.Warn()
+		return z.logger.Warn()
 	case hc.LevelError:
-		event = z.logger.Error()
+		return z.logger.Error()
+	default:
+		return z.logger.Info()
 	}
+}
 
-	for k, v := range fields {
-		switch val := v.(type) {
-		case string:
-			event = event.Str(k, val)
-		case int:
-			event = event.Int(k, val)
-		case int8:
-			event = event.Int8(k, val)
-		case int16:
-			event = event.Int16(k, val)
-		case int32:
-			event = event.Int32(k, val)
-		case int64:
-			event = event.Int64(k, val)
-		case uint:
-			event = event.Uint(k, val)
-		case uint8:
-			event = event.Uint8(k, val)
-		case uint16:
-			event = event.Uint16(k, val)
-		case uint32:
-			event = event.Uint32(k, val)
-		case uint64:
-			event = event.Uint64(k, val)
-		case float32:
-			event = event.Float32(k, val)
-		case float64:
-			event = event.Float64(k, val)
-		case bool:
-			event = event.Bool(k, val)
-		case time.Time:
-			event = event.Time(k, val)
-		case time.Duration:
-			event = event.Dur(k, val)
-		case error:
-			event = event.Str(k, val.Error())
-		default:
-			event = event.Interface(k, v)
-		}
+func appendValue(event *zerolog.Event, k string, v any) *zerolog.Event {
+	switch val := v.(type) {
+	case string:
+		return event.Str(k, val)
+	case int:
+		return event.Int(k, val)
+	case int8:
+		return event.Int8(k, val)
+	case int16:
+		return event.Int16(k, val)
+	case int32:
+		return event.Int32(k, val)
+	case int64:
+		return event.Int64(k, val)
+	case uint:
+		return event.Uint(k, val)
+	case uint8:
+		return event.Uint8(k, val)
+	case uint16:
+		return event.Uint16(k, val)
+	case uint32:
+		return event.Uint32(k, val)
+	case uint64:
+		return event.Uint64(k, val)
+	case float32:
+		return event.Float32(k, val)
+	case float64:
+		return event.Float64(k, val)
+	case bool:
+		return event.Bool(k, val)
+	case time.Time:
+		return event.Time(k, val)
+	case time.Duration:
+		return event.Dur(k, val)
+	case error:
+		return event.Str(k, val.Error())
+	default:
+		return event.Interface(k, v)
 	}
-	event.Msg(message)
 }
 
-var _ hc.Sink = (*Sink)(nil)
+var _ hc.FieldSink = (*Sink)(nil)
@@ -123,3 +123,49 @@ func TestSinkWriteNilSafety(t *testing.T) {
 	sink := New(nil)
 	sink.Write(hc.LevelInfo, "x", map[string]any{"k": 1})
 }
+
+func TestSinkWriteFieldsKeepsTypes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	sink := New(&logger)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	sink.WriteFields(hc.LevelError, "", []hc.Field{
+		hc.String("user_id", "u_1"),
+		hc.Int("http.status", 500),
+		hc.Float64("ratio", 0.5),
+		hc.Bool("retry", true),
+		hc.Duration("wait", 2*time.Second),
+		hc.Time("at", now),
+		hc.Any("err", errors.New("boom")),
+	})
+
+	var payload map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("failed to decode zerolog payload: %v", err)
+	}
+	if payload["level"] != "error" {
+		t.Fatalf("expected error level, got %v", payload["level"])
+	}
+	if payload["message"] != "request_completed" {
+		t.Fatalf("expected default message, got %v", payload["message"])
+	}
+	if payload["user_id"] != "u_1" || payload["http.status"] != float64(500) || payload["ratio"] != 0.5 || payload["retry"] != true {
+		t.Fatalf("unexpected scalar fields: %v", payload)
+	}
+	if payload["wait"] != float64(2000) {
+		t.Fatalf("expected duration in ms, got %v", payload["wait"])
+	}
+	if payload["at"] != now.Format(time.RFC3339) {
+		t.Fatalf("expected time field, got %v", payload["at"])
+	}
+	if payload["err"] != "boom" {
+		t.Fatalf("expected error string, got %v", payload["err"])
+	}
+}
+
+func TestSinkWriteFieldsNilSafe(t *testing.T) {
+	var sink *Sink
+	sink.WriteFields(hc.LevelInfo, "x", []hc.Field{hc.String("k", "v")})
+	New(nil).WriteFields(hc.LevelInfo, "x", nil)
+}
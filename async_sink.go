@@ -0,0 +1,293 @@
+package hc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	defaultAsyncQueueSize = 1024
+	defaultAsyncWorkers   = 1
+)
+
+// OverflowPolicy selects what AsyncSink does when its queue is full.
+type OverflowPolicy uint8
+
+const (
+	// OverflowBlock waits for queue space.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest drops the incoming event.
+	OverflowDropNewest
+	// OverflowDropOldest evicts the oldest queued event to make room.
+	OverflowDropOldest
+	// OverflowDropByLevel drops incoming events below AsyncOptions.KeepLevel.
+	// Events at or above KeepLevel evict the oldest queued event below it,
+	// and wait for space only when every queued event must be kept.
+	OverflowDropByLevel
+)
+
+// AsyncOptions controls AsyncSink behavior.
+type AsyncOptions struct {
+	// QueueSize bounds how many events may wait for delivery. Default is 1024.
+	QueueSize int
+
+	// Workers is the number of delivery goroutines. Default is 1.
+	Workers int
+
+	// Overflow selects the full-queue behavior. Default is OverflowBlock.
+	Overflow OverflowPolicy
+
+	// KeepLevel is the lowest level protected by OverflowDropByLevel. Default is ERROR.
+	KeepLevel Level
+}
+
+// AsyncSink delivers events to another sink from background workers so
+// slow destinations do not add to request latency.
+//
+// The fields map passed to Write is handed to the wrapped sink as-is and
+// must not be modified afterwards. Call Close during shutdown to drain
+// queued events.
+type AsyncSink struct {
+	next      Sink
+	overflow  OverflowPolicy
+	keepLevel Level
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	queue    []asyncEvent
+	head     int
+	size     int
+	inflight int
+	closed   bool
+	idle     chan struct{}
+	done     chan struct{}
+
+	written atomic.Uint64
+	dropped atomic.Uint64
+}
+
+type asyncEvent struct {
+	level   Level
+	message string
+	fields  map[string]any
+}
+
+// NewAsyncSink starts an asynchronous wrapper around next.
+func NewAsyncSink(next Sink, opts AsyncOptions) *AsyncSink {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultAsyncQueueSize
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = defaultAsyncWorkers
+	}
+	if !isValidLevel(opts.KeepLevel) {
+		opts.KeepLevel = LevelError
+	}
+
+	s := &AsyncSink{
+		next:      next,
+		overflow:  opts.Overflow,
+		keepLevel: opts.KeepLevel,
+		queue:     make([]asyncEvent, opts.QueueSize),
+		done:      make(chan struct{}),
+	}
+	s.notEmpty = sync.NewCond(&s.mu)
+	s.notFull = sync.NewCond(&s.mu)
+
+	var wg sync.WaitGroup
+	wg.Add(opts.Workers)
+	for range opts.Workers {
+		go func() {
+			defer wg.Done()
+			s.run()
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(s.done)
+	}()
+	return s
+}
+
+// Write implements Sink by enqueueing the event.
+// Events written after Close are dropped.
+func (s *AsyncSink) Write(level Level, message string, fields map[string]any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	for !s.closed && s.size == len(s.queue) {
+		if !s.makeRoom(level) {
+			s.mu.Unlock()
+			s.dropped.Add(1)
+			return
+		}
+	}
+	if s.closed {
+		s.mu.Unlock()
+		s.dropped.Add(1)
+		return
+	}
+	s.queue[(s.head+s.size)%len(s.queue)] = asyncEvent{level: level, message: message, fields: fields}
+	s.size++
+	s.notEmpty.Signal()
+	s.mu.Unlock()
+}
+
+// Flush waits until every queued event has been delivered or ctx is done.
+func (s *AsyncSink) Flush(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	if s.size == 0 && s.inflight == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	if s.idle == nil {
+		s.idle = make(chan struct{})
+	}
+	idle := s.idle
+	s.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting events, drains the queue, and waits for workers to
+// exit or ctx to be done. Close is safe to call more than once.
+func (s *AsyncSink) Close(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		s.notEmpty.Broadcast()
+		s.notFull.Broadcast()
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Written returns how many events were delivered to the wrapped sink.
+func (s *AsyncSink) Written() uint64 {
+	if s == nil {
+		return 0
+	}
+	return s.written.Load()
+}
+
+// Dropped returns how many events were discarded by the overflow policy,
+// written after Close, or lost to a panicking wrapped sink.
+func (s *AsyncSink) Dropped() uint64 {
+	if s == nil {
+		return 0
+	}
+	return s.dropped.Load()
+}
+
+// makeRoom applies the overflow policy while s.mu is held and the queue is
+// full. It returns false when the incoming event should be dropped.
+func (s *AsyncSink) makeRoom(level Level) bool {
+	switch s.overflow {
+	case OverflowDropNewest:
+		return false
+	case OverflowDropOldest:
+		s.pop()
+		s.dropped.Add(1)
+		return true
+	case OverflowDropByLevel:
+		keepRank := levelRank(s.keepLevel)
+		if levelRank(level) < keepRank {
+			return false
+		}
+		if s.evictBelow(keepRank) {
+			s.dropped.Add(1)
+			return true
+		}
+	}
+	s.notFull.Wait()
+	return true
+}
+
+func (s *AsyncSink) pop() asyncEvent {
+	ev := s.queue[s.head]
+	s.queue[s.head] = asyncEvent{}
+	s.head = (s.head + 1) % len(s.queue)
+	s.size--
+	return ev
+}
+
+// evictBelow removes the oldest queued event ranked below keepRank.
+func (s *AsyncSink) evictBelow(keepRank int) bool {
+	n := len(s.queue)
+	for i := range s.size {
+		idx := (s.head + i) % n
+		if levelRank(s.queue[idx].level) >= keepRank {
+			continue
+		}
+		for j := i; j < s.size-1; j++ {
+			s.queue[(s.head+j)%n] = s.queue[(s.head+j+1)%n]
+		}
+		s.queue[(s.head+s.size-1)%n] = asyncEvent{}
+		s.size--
+		return true
+	}
+	return false
+}
+
+func (s *AsyncSink) run() {
+	for {
+		s.mu.Lock()
+		for s.size == 0 && !s.closed {
+			s.notEmpty.Wait()
+		}
+		if s.size == 0 {
+			s.mu.Unlock()
+			return
+		}
+		ev := s.pop()
+		s.inflight++
+		s.notFull.Signal()
+		s.mu.Unlock()
+
+		s.deliver(ev)
+
+		s.mu.Lock()
+		s.inflight--
+		if s.size == 0 && s.inflight == 0 && s.idle != nil {
+			close(s.idle)
+			s.idle = nil
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *AsyncSink) deliver(ev asyncEvent) {
+	defer func() {
+		if recover() != nil {
+			s.dropped.Add(1)
+		}
+	}()
+	if s.next == nil {
+		s.dropped.Add(1)
+		return
+	}
+	s.next.Write(ev.level, ev.message, ev.fields)
+	s.written.Add(1)
+}
+
+var _ Sink = (*AsyncSink)(nil)
@@ -0,0 +1,193 @@
+package hc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAsyncSinkDeliversAndFlushes(t *testing.T) {
+	backend := NewTestSink()
+	sink := NewAsyncSink(backend, AsyncOptions{Workers: 2})
+
+	for range 50 {
+		sink.Write(LevelInfo, "ok", map[string]any{"k": "v"})
+	}
+	if err := sink.Flush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	if got := len(backend.Events()); got != 50 {
+		t.Fatalf("delivered %d events, want 50", got)
+	}
+	if sink.Written() != 50 || sink.Dropped() != 0 {
+		t.Fatalf("written=%d dropped=%d", sink.Written(), sink.Dropped())
+	}
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
+
+func TestAsyncSinkOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      OverflowPolicy
+		writes      []Level
+		wantMessage []string
+		wantDropped uint64
+	}{
+		{
+			name:        "drop newest",
+			policy:      OverflowDropNewest,
+			writes:      []Level{LevelInfo, LevelInfo, LevelInfo},
+			wantMessage: []string{"0", "1"},
+			wantDropped: 1,
+		},
+		{
+			name:        "drop oldest",
+			policy:      OverflowDropOldest,
+			writes:      []Level{LevelInfo, LevelInfo, LevelInfo},
+			wantMessage: []string{"1", "2"},
+			wantDropped: 1,
+		},
+		{
+			name:        "drop by level keeps errors",
+			policy:      OverflowDropByLevel,
+			writes:      []Level{LevelError, LevelInfo, LevelWarn, LevelError},
+			wantMessage: []string{"0", "3"},
+			wantDropped: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := newGateSink()
+			sink := NewAsyncSink(backend, AsyncOptions{QueueSize: 2, Overflow: tt.policy})
+
+			// Park the single worker on a first event so the queue fills deterministically.
+			sink.Write(LevelInfo, "first", nil)
+			<-backend.started
+
+			for i, level := range tt.writes {
+				sink.Write(level, string(rune('0'+i)), nil)
+			}
+			close(backend.release)
+			if err := sink.Close(context.Background()); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+
+			got := backend.messages()
+			want := append([]string{"first"}, tt.wantMessage...)
+			if len(got) != len(want) {
+				t.Fatalf("messages = %v, want %v", got, want)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("messages = %v, want %v", got, want)
+				}
+			}
+			if sink.Dropped() != tt.wantDropped {
+				t.Fatalf("dropped = %d, want %d", sink.Dropped(), tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestAsyncSinkBlockPolicyWaitsForSpace(t *testing.T) {
+	backend := newGateSink()
+	sink := NewAsyncSink(backend, AsyncOptions{QueueSize: 1})
+
+	sink.Write(LevelInfo, "first", nil)
+	<-backend.started
+	sink.Write(LevelInfo, "queued", nil)
+
+	written := make(chan struct{})
+	go func() {
+		sink.Write(LevelInfo, "blocked", nil)
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("expected write to block on full queue")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(backend.release)
+	<-written
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if got := len(backend.messages()); got != 3 {
+		t.Fatalf("delivered %d events, want 3", got)
+	}
+	if sink.Dropped() != 0 {
+		t.Fatalf("dropped = %d, want 0", sink.Dropped())
+	}
+}
+
+func TestAsyncSinkFlushHonorsContext(t *testing.T) {
+	backend := newGateSink()
+	sink := NewAsyncSink(backend, AsyncOptions{})
+	sink.Write(LevelInfo, "stuck", nil)
+	<-backend.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := sink.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("flush error = %v, want deadline exceeded", err)
+	}
+
+	close(backend.release)
+	if err := sink.Flush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	_ = sink.Close(context.Background())
+}
+
+func TestAsyncSinkDropsAfterCloseAndRecoversPanics(t *testing.T) {
+	sink := NewAsyncSink(panicSink{}, AsyncOptions{})
+	sink.Write(LevelInfo, "boom", nil)
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatalf("second close: %v", err)
+	}
+	sink.Write(LevelInfo, "late", nil)
+
+	if sink.Written() != 0 {
+		t.Fatalf("written = %d, want 0", sink.Written())
+	}
+	if sink.Dropped() != 2 {
+		t.Fatalf("dropped = %d, want 2", sink.Dropped())
+	}
+}
+
+type gateSink struct {
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	got     []string
+}
+
+func newGateSink() *gateSink {
+	return &gateSink{started: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (g *gateSink) Write(_ Level, message string, _ map[string]any) {
+	g.once.Do(func() { close(g.started) })
+	<-g.release
+	g.mu.Lock()
+	g.got = append(g.got, message)
+	g.mu.Unlock()
+}
+
+func (g *gateSink) messages() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]string(nil), g.got...)
+}
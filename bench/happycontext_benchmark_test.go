@@ -25,6 +25,34 @@ func BenchmarkEventAddStableKeys(b *testing.B) {
 	}
 }
 
+func BenchmarkEventAddFieldsStableKeys(b *testing.B) {
+	ctx, _ := hc.NewContext(context.Background())
+	keys := make([]string, 32)
+	for i := range keys {
+		keys[i] = "k" + strconv.Itoa(i)
+	}
+
+	b.ReportAllocs()
+	i := 0
+	for b.Loop() {
+		hc.AddFields(ctx, hc.Int(keys[i&31], i))
+		i++
+	}
+}
+
+func BenchmarkEventIncParallel(b *testing.B) {
+	ctx, _ := hc.NewContext(context.Background())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			hc.Inc(ctx, "cache.hits", 1)
+		}
+	})
+}
+
 func BenchmarkEventAddMany(b *testing.B) {
 	b.ReportAllocs()
 	for b.Loop() {
@@ -126,10 +154,34 @@ func BenchmarkCommitPath(b *testing.B) {
 	}
 }
 
+func BenchmarkCommitPathTyped(b *testing.B) {
+	sink := discardFieldSink{}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		ctx, _ := hc.NewContext(context.Background())
+		hc.AddFields(
+			ctx,
+			hc.String("http.method", "GET"),
+			hc.String("http.path", "/checkout"),
+			hc.Int("http.status", 200),
+			hc.Int("duration_ms", 12),
+			hc.String("user_id", "u_8472"),
+			hc.String("user_plan", "premium"),
+			hc.Int("db.query_count", 3),
+		)
+		hc.Commit(ctx, sink, hc.LevelInfo)
+	}
+}
+
 type discardSink struct{}
 
 func (discardSink) Write(_ hc.Level, _ string, _ map[string]any) {}
 
+type discardFieldSink struct{ discardSink }
+
+func (discardFieldSink) WriteFields(_ hc.Level, _ string, _ []hc.Field) {}
+
 func BenchmarkJSONEncodingReference(b *testing.B) {
 	payload := []byte(`{"status":"ok"}`)
 	b.ReportAllocs()
@@ -26,6 +26,10 @@ type discardSink struct{}
 
 func (discardSink) Write(hc.Level, string, map[string]any) {}
 
+type discardFieldSink struct{ discardSink }
+
+func (discardFieldSink) WriteFields(hc.Level, string, []hc.Field) {}
+
 type noopSlogHandler struct{}
 
 func (noopSlogHandler) Enabled(context.Context, slog.Level) bool  { return true }
@@ -50,6 +54,16 @@ func BenchmarkRouter_std(b *testing.B) {
 		}
 	})
 
+	b.Run("middleware_on_field_sink_noop", func(b *testing.B) {
+		mw := stdhc.Middleware(hc.Config{Sink: discardFieldSink{}, SamplingRate: 1})
+		wrapped := mw(handlerHappycontextAPI)
+		b.ReportAllocs()
+		for b.Loop() {
+			rr := httptest.NewRecorder()
+			wrapped.ServeHTTP(rr, req)
+		}
+	})
+
 	b.Run("normal_logging_slog_noop_handler_no_middleware", func(b *testing.B) {
 		logger := slog.New(noopSlogHandler{})
 		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
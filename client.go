@@ -0,0 +1,144 @@
+package hc
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	defaultClientField    = "http.client"
+	defaultClientMaxCalls = 16
+)
+
+// ClientOptions controls outbound HTTP call recording.
+type ClientOptions struct {
+	// Field is the event field that receives the call summary. Default is "http.client".
+	Field string
+
+	// MaxCalls caps how many individual call records are kept. Default is 16.
+	// Aggregate counts and durations always include every call.
+	MaxCalls int
+
+	// Label optionally names the downstream route of a call, e.g. "payments.charge".
+	// Empty labels are omitted.
+	Label func(*http.Request) string
+}
+
+// Transport is an http.RoundTripper that summarizes outbound calls on the
+// event stored in each request's context.
+//
+// The summary field holds count, errors (transport failures and 5xx
+// responses), total duration_ms, the first MaxCalls call records, and the
+// number of dropped records. Requests without an event are passed through untouched.
+type Transport struct {
+	next     http.RoundTripper
+	field    string
+	maxCalls int
+	label    func(*http.Request) string
+}
+
+// NewTransport wraps next with default options.
+// A nil next uses http.DefaultTransport.
+func NewTransport(next http.RoundTripper) *Transport {
+	return NewTransportWithOptions(next, ClientOptions{})
+}
+
+// NewTransportWithOptions wraps next with options.
+// A nil next uses http.DefaultTransport.
+func NewTransportWithOptions(next http.RoundTripper, opts ClientOptions) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if opts.Field == "" {
+		opts.Field = defaultClientField
+	}
+	if opts.MaxCalls <= 0 {
+		opts.MaxCalls = defaultClientMaxCalls
+	}
+	return &Transport{
+		next:     next,
+		field:    opts.Field,
+		maxCalls: opts.MaxCalls,
+		label:    opts.Label,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+//
+// Call duration covers the time until response headers are received.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	e := FromContext(req.Context())
+	if e == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+
+	call := map[string]any{
+		"host":        req.URL.Host,
+		"method":      req.Method,
+		"duration_ms": durationMillis(duration),
+	}
+	if t.label != nil {
+		if label := t.label(req); label != "" {
+			call["route"] = label
+		}
+	}
+	failed := err != nil
+	if err != nil {
+		call["error"] = err.Error()
+	} else {
+		call["status"] = resp.StatusCode
+		failed = resp.StatusCode >= 500
+	}
+
+	updateAggregate(e, t.field, newClientCalls, func(c *clientCalls) {
+		c.record(call, duration, failed, t.maxCalls)
+	})
+	return resp, err
+}
+
+// clientCalls accumulates outbound call records for one event field.
+type clientCalls struct {
+	count    int
+	failures int
+	total    time.Duration
+	calls    []any
+}
+
+func newClientCalls() *clientCalls {
+	return &clientCalls{}
+}
+
+func (c *clientCalls) record(call map[string]any, duration time.Duration, failed bool, maxCalls int) {
+	c.count++
+	c.total += duration
+	if failed {
+		c.failures++
+	}
+	if len(c.calls) < maxCalls {
+		c.calls = append(c.calls, call)
+	}
+}
+
+func (c *clientCalls) fieldValue() any {
+	// Call records are never mutated after being recorded, so they can be
+	// shared between snapshots; only the containers are copied.
+	calls := make([]any, len(c.calls))
+	copy(calls, c.calls)
+	return map[string]any{
+		"count":       c.count,
+		"errors":      c.failures,
+		"duration_ms": durationMillis(c.total),
+		"calls":       calls,
+		"dropped":     c.count - len(c.calls),
+	}
+}
+
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
@@ -0,0 +1,159 @@
+package hc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTransportRecordsCallsOnEvent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewTransportWithOptions(nil, ClientOptions{
+		Label: func(r *http.Request) string { return "svc" + r.URL.Path },
+	})}
+	ctx, e := NewContext(context.Background())
+
+	for _, path := range []string{"/ok", "/fail"} {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+path, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	summary, ok := EventFields(e)[defaultClientField].(map[string]any)
+	if !ok {
+		t.Fatalf("expected client summary field, got %T", EventFields(e)[defaultClientField])
+	}
+	if summary["count"] != 2 || summary["errors"] != 1 || summary["dropped"] != 0 {
+		t.Fatalf("unexpected aggregates: %v", summary)
+	}
+	calls := summary["calls"].([]any)
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 call records, got %d", len(calls))
+	}
+	first := calls[0].(map[string]any)
+	if first["method"] != http.MethodGet || first["status"] != http.StatusOK || first["route"] != "svc/ok" {
+		t.Fatalf("unexpected call record: %v", first)
+	}
+	if first["host"] != strings.TrimPrefix(srv.URL, "http://") {
+		t.Fatalf("host = %v", first["host"])
+	}
+}
+
+func TestTransportCapsCallsAndKeepsAggregatesExact(t *testing.T) {
+	next := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}, nil
+	})
+	transport := NewTransportWithOptions(next, ClientOptions{MaxCalls: 2, Field: "downstream"})
+	ctx, e := NewContext(context.Background())
+
+	const n = 20
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for range n {
+		go func() {
+			defer wg.Done()
+			r, _ := http.NewRequestWithContext(ctx, http.MethodPost, "http://api.internal/x", nil)
+			_, _ = transport.RoundTrip(r)
+		}()
+	}
+	wg.Wait()
+
+	summary := EventFields(e)["downstream"].(map[string]any)
+	if summary["count"] != n {
+		t.Fatalf("count = %v, want %d", summary["count"], n)
+	}
+	if len(summary["calls"].([]any)) != 2 {
+		t.Fatalf("expected calls capped at 2, got %d", len(summary["calls"].([]any)))
+	}
+	if summary["dropped"] != n-2 {
+		t.Fatalf("dropped = %v, want %d", summary["dropped"], n-2)
+	}
+}
+
+func TestTransportRecordsTransportErrors(t *testing.T) {
+	boom := errors.New("dial failed")
+	transport := NewTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, boom
+	}))
+	ctx, e := NewContext(context.Background())
+
+	r, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://api.internal/x", nil)
+	if _, err := transport.RoundTrip(r); !errors.Is(err, boom) {
+		t.Fatalf("expected transport error, got %v", err)
+	}
+
+	summary := EventFields(e)[defaultClientField].(map[string]any)
+	if summary["errors"] != 1 {
+		t.Fatalf("errors = %v, want 1", summary["errors"])
+	}
+	call := summary["calls"].([]any)[0].(map[string]any)
+	if call["error"] != "dial failed" {
+		t.Fatalf("error = %v", call["error"])
+	}
+	if _, ok := call["status"]; ok {
+		t.Fatal("did not expect status on failed call")
+	}
+}
+
+func TestTransportSnapshotsAreIndependent(t *testing.T) {
+	transport := NewTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+	ctx, e := NewContext(context.Background())
+	r, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://api.internal/x", nil)
+
+	_, _ = transport.RoundTrip(r)
+	before := EventFields(e)[defaultClientField].(map[string]any)
+	_, _ = transport.RoundTrip(r)
+
+	if before["count"] != 1 || len(before["calls"].([]any)) != 1 {
+		t.Fatalf("expected earlier snapshot to stay unchanged, got %v", before)
+	}
+}
+
+func TestTransportPassesThroughWithoutEvent(t *testing.T) {
+	called := false
+	transport := NewTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+	r, _ := http.NewRequest(http.MethodGet, "http://api.internal/x", nil)
+	if _, err := transport.RoundTrip(r); err != nil || !called {
+		t.Fatalf("expected passthrough, called=%v err=%v", called, err)
+	}
+}
+
+func TestAddOverridesAggregateField(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	transport := NewTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+	r, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://api.internal/x", nil)
+	_, _ = transport.RoundTrip(r)
+
+	Add(ctx, defaultClientField, "manual")
+	if got := EventFields(e)[defaultClientField]; got != "manual" {
+		t.Fatalf("field = %v, want manual", got)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
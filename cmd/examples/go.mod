@@ -4,17 +4,23 @@ go 1.25.0
 
 require (
 	github.com/gin-gonic/gin v1.11.0
+	github.com/go-chi/chi/v5 v5.3.2
 	github.com/gofiber/fiber/v2 v2.52.11
 	github.com/gofiber/fiber/v3 v3.0.0-rc.3
+	github.com/gorilla/mux v1.8.1
 	github.com/happytoolin/happycontext v0.2.4
 	github.com/happytoolin/happycontext/adapter/slog v0.0.0
 	github.com/happytoolin/happycontext/adapter/zap v0.0.0
 	github.com/happytoolin/happycontext/adapter/zerolog v0.0.0
+	github.com/happytoolin/happycontext/integration/chi v0.0.0
 	github.com/happytoolin/happycontext/integration/echo v0.0.0
 	github.com/happytoolin/happycontext/integration/fiber v0.0.0
 	github.com/happytoolin/happycontext/integration/fiberv3 v0.0.0
 	github.com/happytoolin/happycontext/integration/gin v0.0.0
-	github.com/happytoolin/happycontext/integration/std v0.0.0
+	github.com/happytoolin/happycontext/integration/gorillamux v0.0.0
+	github.com/happytoolin/happycontext/integration/httprouter v0.0.0
+	github.com/happytoolin/happycontext/integration/std v0.2.4
+	github.com/julienschmidt/httprouter v1.3.0
 	github.com/labstack/echo/v4 v4.15.0
 	github.com/rs/zerolog v1.34.0
 	go.uber.org/zap v1.27.1
@@ -78,8 +84,14 @@ replace github.com/happytoolin/happycontext/adapter/zap => ../../adapter/zap
 
 replace github.com/happytoolin/happycontext/adapter/zerolog => ../../adapter/zerolog
 
+replace github.com/happytoolin/happycontext/integration/chi => ../../integration/chi
+
 replace github.com/happytoolin/happycontext/integration/gin => ../../integration/gin
 
+replace github.com/happytoolin/happycontext/integration/gorillamux => ../../integration/gorillamux
+
+replace github.com/happytoolin/happycontext/integration/httprouter => ../../integration/httprouter
+
 replace github.com/happytoolin/happycontext/integration/echo => ../../integration/echo
 
 replace github.com/happytoolin/happycontext/integration/fiber => ../../integration/fiber
@@ -2,22 +2,30 @@ package examples
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-chi/chi/v5"
 	"github.com/gofiber/fiber/v2"
 	recoverv2 "github.com/gofiber/fiber/v2/middleware/recover"
 	fiberv3 "github.com/gofiber/fiber/v3"
 	recoverv3 "github.com/gofiber/fiber/v3/middleware/recover"
+	"github.com/gorilla/mux"
 	"github.com/happytoolin/happycontext"
+	chihappycontext "github.com/happytoolin/happycontext/integration/chi"
 	echohappycontext "github.com/happytoolin/happycontext/integration/echo"
 	fiberhappycontext "github.com/happytoolin/happycontext/integration/fiber"
 	fiberv3happycontext "github.com/happytoolin/happycontext/integration/fiberv3"
 	ginhappycontext "github.com/happytoolin/happycontext/integration/gin"
+	gorillamuxhappycontext "github.com/happytoolin/happycontext/integration/gorillamux"
+	httprouterhappycontext "github.com/happytoolin/happycontext/integration/httprouter"
 	stdhappycontext "github.com/happytoolin/happycontext/integration/std"
+	"github.com/julienschmidt/httprouter"
 	"github.com/labstack/echo/v4"
 )
 
@@ -36,31 +44,58 @@ type comparableResult struct {
 	hasPanic bool
 }
 
-func TestIntegrationConsistency(t *testing.T) {
-	gin.SetMode(gin.TestMode)
+// consistencyRunner drives one HTTP integration through the shared
+// consistency scenarios.
+type consistencyRunner struct {
+	name string
+	// run serves /orders/1 in a success, error, or panic mode.
+	run func(t *testing.T, mode string) runResult
+	// serve serves req with cfg through a handler that reads the body, sets
+	// the X-Region and Set-Cookie response headers, and writes "ok".
+	serve func(t *testing.T, cfg hc.Config, req *http.Request) http.Header
+	// route is the template serve registers for /orders/1, if any.
+	route string
+	// implicitError, when set, serves a handler that returns an error
+	// without setting a status.
+	implicitError func(t *testing.T) runResult
+	// clientError, when set, serves a handler that fails /orders/1 with a
+	// 400 error the middleware sees.
+	clientError func(t *testing.T, cfg hc.Config)
+}
 
-	type runner struct {
-		name string
-		run  func(t *testing.T, mode string) runResult
-	}
-	runners := []runner{
-		{name: "std", run: runStd},
-		{name: "gin", run: runGin},
-		{name: "echo", run: runEcho},
-		{name: "fiber", run: runFiber},
-		{name: "fiberv3", run: runFiberV3},
+var consistencyRunners = []consistencyRunner{
+	{name: "std", run: runStd, serve: serveStd, clientError: runStdClientError},
+	{name: "chi", run: runChi, serve: serveChi, route: "/orders/{id}"},
+	{name: "gorillamux", run: runGorillaMux, serve: serveGorillaMux, route: "/orders/{id}"},
+	{name: "httprouter", run: runHTTPRouter, serve: serveHTTPRouter, route: "/orders/:id"},
+	{name: "gin", run: runGin, serve: serveGin, route: "/orders/:id", implicitError: runGinImplicitError, clientError: runGinClientError},
+	{name: "echo", run: runEcho, serve: serveEcho, route: "/orders/:id", implicitError: runEchoImplicitError, clientError: runEchoClientError},
+	{name: "fiber", run: runFiber, serve: serveFiber, route: "/orders/:id", implicitError: runFiberImplicitError, clientError: runFiberClientError},
+	{name: "fiberv3", run: runFiberV3, serve: serveFiberV3, route: "/orders/:id", implicitError: runFiberV3ImplicitError, clientError: runFiberV3ClientError},
+}
+
+// forEachRunner runs fn as a subtest for every runner in consistencyRunners.
+func forEachRunner(t *testing.T, fn func(t *testing.T, r consistencyRunner)) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	for _, r := range consistencyRunners {
+		t.Run(r.name, func(t *testing.T) { fn(t, r) })
 	}
+}
+
+func TestIntegrationConsistency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
 
 	modes := []string{"success", "error", "panic"}
 	for _, mode := range modes {
 		t.Run(mode, func(t *testing.T) {
-			results := make(map[string]runResult, len(runners))
-			for _, r := range runners {
+			results := make(map[string]runResult, len(consistencyRunners))
+			for _, r := range consistencyRunners {
 				results[r.name] = r.run(t, mode)
 			}
 
 			var baseline comparableResult
-			for i, r := range runners {
+			for i, r := range consistencyRunners {
 				out := results[r.name]
 				assertConsistency(t, mode, out)
 				got := normalizeResult(t, out)
@@ -122,34 +157,163 @@ func assertConsistency(t *testing.T, mode string, out runResult) {
 }
 
 func TestIntegrationImplicitErrorStatusConsistency(t *testing.T) {
-	gin.SetMode(gin.TestMode)
+	forEachRunner(t, func(t *testing.T, r consistencyRunner) {
+		if r.implicitError == nil {
+			t.Skip("handlers cannot return errors")
+		}
+		out := r.implicitError(t)
+		status := statusFromField(t, out.event.Fields["http.status"])
+		if status != http.StatusInternalServerError {
+			t.Fatalf("status = %d, want %d", status, http.StatusInternalServerError)
+		}
+		if out.event.Level != hc.LevelError {
+			t.Fatalf("level = %s, want ERROR", out.event.Level)
+		}
+		if _, ok := out.event.Fields["error"].(map[string]any); !ok {
+			t.Fatalf("expected structured error field")
+		}
+	})
+}
 
-	type runner struct {
-		name string
-		run  func(t *testing.T) runResult
-	}
-	runners := []runner{
-		{name: "gin", run: runGinImplicitError},
-		{name: "echo", run: runEchoImplicitError},
-		{name: "fiber", run: runFiberImplicitError},
-		{name: "fiberv3", run: runFiberV3ImplicitError},
+func TestIntegrationReturnedClientErrorConsistency(t *testing.T) {
+	forEachRunner(t, func(t *testing.T, r consistencyRunner) {
+		if r.clientError == nil {
+			t.Skip("covered by the std runner")
+		}
+		sink := hc.NewTestSink()
+		r.clientError(t, hc.Config{Sink: sink, SamplingRate: 1})
+		event := onlyEvent(t, sink)
+		if status := statusFromField(t, event.Fields["http.status"]); status != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", status, http.StatusBadRequest)
+		}
+		if event.Level != hc.LevelWarn {
+			t.Fatalf("level = %s, want WARN", event.Level)
+		}
+
+		sampled := hc.NewTestSink()
+		r.clientError(t, hc.Config{Sink: sampled, SamplingRate: 0})
+		if got := len(sampled.Events()); got != 0 {
+			t.Fatalf("expected client error to be sampled out, got %d events", got)
+		}
+	})
+}
+
+func TestIntegrationRequestIDConsistency(t *testing.T) {
+	forEachRunner(t, func(t *testing.T, r consistencyRunner) {
+		sink := hc.NewTestSink()
+		cfg := hc.Config{
+			Sink:         sink,
+			SamplingRate: 1,
+			RequestID: hc.RequestIDConfig{
+				Enabled: true,
+				Headers: []string{"X-Request-ID", "X-Correlation-ID"},
+			},
+		}
+		req := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+		req.Header.Set("X-Correlation-ID", "corr-123")
+
+		header := r.serve(t, cfg, req)
+		event := onlyEvent(t, sink)
+		if event.Fields["request_id"] != "corr-123" {
+			t.Fatalf("request_id = %v, want corr-123", event.Fields["request_id"])
+		}
+		if got := header.Get("X-Request-ID"); got != "corr-123" {
+			t.Fatalf("response X-Request-ID = %q, want corr-123", got)
+		}
+	})
+}
+
+func TestIntegrationHeaderCaptureConsistency(t *testing.T) {
+	want := map[string]any{
+		"http.request.header.user-agent":    "examples-test",
+		"http.request.header.x-tenant":      "acme",
+		"http.request.header.authorization": "[REDACTED]",
+		"http.response.header.x-region":     "eu",
+		"http.response.header.set-cookie":   "[REDACTED]",
 	}
 
-	for _, r := range runners {
-		t.Run(r.name, func(t *testing.T) {
-			out := r.run(t)
-			status := statusFromField(t, out.event.Fields["http.status"])
-			if status != http.StatusInternalServerError {
-				t.Fatalf("status = %d, want %d", status, http.StatusInternalServerError)
-			}
-			if out.event.Level != hc.LevelError {
-				t.Fatalf("level = %s, want ERROR", out.event.Level)
-			}
-			if _, ok := out.event.Fields["error"].(map[string]any); !ok {
-				t.Fatalf("expected structured error field")
+	forEachRunner(t, func(t *testing.T, r consistencyRunner) {
+		sink := hc.NewTestSink()
+		cfg := hc.Config{
+			Sink:         sink,
+			SamplingRate: 1,
+			Headers: hc.HeaderConfig{
+				Request:  []string{"User-Agent", "X-Tenant", "Authorization", "X-Missing"},
+				Response: []string{"X-Region", "Set-Cookie"},
+			},
+		}
+		req := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+		req.Header.Set("User-Agent", "examples-test")
+		req.Header.Set("X-Tenant", "acme")
+		req.Header.Set("Authorization", "Bearer secret")
+
+		r.serve(t, cfg, req)
+		fields := onlyEvent(t, sink).Fields
+		for key, value := range want {
+			if fields[key] != value {
+				t.Fatalf("%s = %v, want %v", key, fields[key], value)
 			}
-		})
+		}
+		if _, ok := fields["http.request.header.x-missing"]; ok {
+			t.Fatal("expected absent header not to be recorded")
+		}
+	})
+}
+
+func TestIntegrationBodySizeConsistency(t *testing.T) {
+	forEachRunner(t, func(t *testing.T, r consistencyRunner) {
+		sink := hc.NewTestSink()
+		req := httptest.NewRequest(http.MethodGet, "/orders/1", strings.NewReader("hello"))
+		r.serve(t, hc.Config{Sink: sink, SamplingRate: 1}, req)
+
+		fields := onlyEvent(t, sink).Fields
+		if fields["http.request.body_size"] != int64(5) {
+			t.Fatalf("request body size = %v, want 5", fields["http.request.body_size"])
+		}
+		if fields["http.response.body_size"] != int64(2) {
+			t.Fatalf("response body size = %v, want 2", fields["http.response.body_size"])
+		}
+	})
+}
+
+func TestIntegrationClientAddressConsistency(t *testing.T) {
+	// httptest requests come from 192.0.2.1; fiber test connections from 0.0.0.0.
+	trusted := []netip.Prefix{
+		netip.MustParsePrefix("192.0.2.1/32"),
+		netip.MustParsePrefix("0.0.0.0/32"),
+		netip.MustParsePrefix("10.0.0.0/8"),
 	}
+
+	forEachRunner(t, func(t *testing.T, r consistencyRunner) {
+		sink := hc.NewTestSink()
+		req := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+		req.Header.Set("X-Forwarded-For", "198.51.100.4, 203.0.113.7, 10.0.0.2")
+		r.serve(t, hc.Config{Sink: sink, SamplingRate: 1, TrustedProxies: trusted}, req)
+
+		if got := onlyEvent(t, sink).Fields["client.address"]; got != "203.0.113.7" {
+			t.Fatalf("client.address = %v, want 203.0.113.7", got)
+		}
+	})
+}
+
+func TestIntegrationExclusionConsistency(t *testing.T) {
+	forEachRunner(t, func(t *testing.T, r consistencyRunner) {
+		sink := hc.NewTestSink()
+		cfg := hc.Config{Sink: sink, SamplingRate: 1, Exclude: hc.ExcludeConfig{PathGlobs: []string{"/orders/*"}}}
+		r.serve(t, cfg, httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+		if n := len(sink.Events()); n != 0 {
+			t.Fatalf("expected excluded path to emit no events, got %d", n)
+		}
+
+		if r.route == "" {
+			return
+		}
+		cfg.Exclude = hc.ExcludeConfig{Routes: []string{r.route}}
+		r.serve(t, cfg, httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+		if n := len(sink.Events()); n != 0 {
+			t.Fatalf("expected excluded route to emit no events, got %d", n)
+		}
+	})
 }
 
 func runStd(t *testing.T, mode string) runResult {
@@ -180,6 +344,96 @@ func runStd(t *testing.T, mode string) runResult {
 	return runResult{event: onlyEvent(t, sink), panicObserved: panicObserved}
 }
 
+func runChi(t *testing.T, mode string) runResult {
+	t.Helper()
+	sink := hc.NewTestSink()
+	orders := chi.NewRouter()
+	orders.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
+		switch mode {
+		case "error":
+			hc.Error(r.Context(), errors.New("boom"))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		case "panic":
+			panic("boom")
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	r := chi.NewRouter()
+	r.Use(chihappycontext.Middleware(hc.Config{Sink: sink, SamplingRate: 1}))
+	r.Mount("/orders", orders)
+	var panicObserved bool
+	func() {
+		defer func() {
+			if recover() != nil {
+				panicObserved = true
+			}
+		}()
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+	}()
+	event := onlyEvent(t, sink)
+	if event.Fields["http.route"] != "/orders/{id}" {
+		t.Fatalf("chi route = %v, want /orders/{id}", event.Fields["http.route"])
+	}
+	return runResult{event: event, panicObserved: panicObserved}
+}
+
+func runGorillaMux(t *testing.T, mode string) runResult {
+	t.Helper()
+	sink := hc.NewTestSink()
+	r := mux.NewRouter()
+	r.Use(gorillamuxhappycontext.Middleware(hc.Config{Sink: sink, SamplingRate: 1}))
+	r.HandleFunc("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		switch mode {
+		case "error":
+			hc.Error(r.Context(), errors.New("boom"))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		case "panic":
+			panic("boom")
+		}
+		w.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodGet)
+	var panicObserved bool
+	func() {
+		defer func() {
+			if recover() != nil {
+				panicObserved = true
+			}
+		}()
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+	}()
+	return runResult{event: onlyEvent(t, sink), panicObserved: panicObserved}
+}
+
+func runHTTPRouter(t *testing.T, mode string) runResult {
+	t.Helper()
+	sink := hc.NewTestSink()
+	router := httprouterhappycontext.New()
+	router.GET("/orders/:id", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		switch mode {
+		case "error":
+			hc.Error(r.Context(), errors.New("boom"))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		case "panic":
+			panic("boom")
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	h := httprouterhappycontext.Middleware(hc.Config{Sink: sink, SamplingRate: 1})(router)
+	var panicObserved bool
+	func() {
+		defer func() {
+			if recover() != nil {
+				panicObserved = true
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+	}()
+	return runResult{event: onlyEvent(t, sink), panicObserved: panicObserved}
+}
+
 func runGin(t *testing.T, mode string) runResult {
 	t.Helper()
 	sink := hc.NewTestSink()
@@ -324,6 +578,178 @@ func runFiberV3ImplicitError(t *testing.T) runResult {
 	return runResult{event: onlyEvent(t, sink)}
 }
 
+// runStdClientError records the error with an explicit class, since
+// net/http handlers cannot return one to the middleware.
+func runStdClientError(t *testing.T, cfg hc.Config) {
+	t.Helper()
+	h := stdhappycontext.Middleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hc.Error(r.Context(), errors.New("invalid order id"), hc.ErrorClassClient)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+}
+
+func runGinClientError(t *testing.T, cfg hc.Config) {
+	t.Helper()
+	r := gin.New()
+	r.Use(ginhappycontext.Middleware(cfg))
+	r.GET("/orders/:id", func(c *gin.Context) {
+		_ = c.Error(errors.New("invalid order id"))
+		c.AbortWithStatus(http.StatusBadRequest)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+}
+
+func runEchoClientError(t *testing.T, cfg hc.Config) {
+	t.Helper()
+	e := echo.New()
+	e.Use(echohappycontext.Middleware(cfg))
+	e.GET("/orders/:id", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid order id")
+	})
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+}
+
+func runFiberClientError(t *testing.T, cfg hc.Config) {
+	t.Helper()
+	app := fiber.New()
+	app.Use(fiberhappycontext.Middleware(cfg))
+	app.Get("/orders/:id", func(c *fiber.Ctx) error {
+		return fiber.NewError(http.StatusBadRequest, "invalid order id")
+	})
+	_, _ = app.Test(httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+}
+
+func runFiberV3ClientError(t *testing.T, cfg hc.Config) {
+	t.Helper()
+	app := fiberv3.New()
+	app.Use(fiberv3happycontext.Middleware(cfg))
+	app.Get("/orders/:id", func(c fiberv3.Ctx) error {
+		return fiberv3.NewError(http.StatusBadRequest, "invalid order id")
+	})
+	_, _ = app.Test(httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+}
+
+func serveStd(t *testing.T, cfg hc.Config, req *http.Request) http.Header {
+	t.Helper()
+	h := stdhappycontext.Middleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Region", "eu")
+		w.Header().Set("Set-Cookie", "sid=1")
+		_, _ = io.Copy(io.Discard, r.Body)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr.Header()
+}
+
+func serveChi(t *testing.T, cfg hc.Config, req *http.Request) http.Header {
+	t.Helper()
+	r := chi.NewRouter()
+	r.Use(chihappycontext.Middleware(cfg))
+	r.Get("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Region", "eu")
+		w.Header().Set("Set-Cookie", "sid=1")
+		_, _ = io.Copy(io.Discard, r.Body)
+		_, _ = w.Write([]byte("ok"))
+	})
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr.Header()
+}
+
+func serveGorillaMux(t *testing.T, cfg hc.Config, req *http.Request) http.Header {
+	t.Helper()
+	r := mux.NewRouter()
+	r.HandleFunc("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Region", "eu")
+		w.Header().Set("Set-Cookie", "sid=1")
+		_, _ = io.Copy(io.Discard, r.Body)
+		_, _ = w.Write([]byte("ok"))
+	})
+	rr := httptest.NewRecorder()
+	gorillamuxhappycontext.Middleware(cfg)(r).ServeHTTP(rr, req)
+	return rr.Header()
+}
+
+func serveHTTPRouter(t *testing.T, cfg hc.Config, req *http.Request) http.Header {
+	t.Helper()
+	router := httprouterhappycontext.New()
+	router.GET("/orders/:id", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		w.Header().Set("X-Region", "eu")
+		w.Header().Set("Set-Cookie", "sid=1")
+		_, _ = io.Copy(io.Discard, r.Body)
+		_, _ = w.Write([]byte("ok"))
+	})
+	rr := httptest.NewRecorder()
+	httprouterhappycontext.Middleware(cfg)(router).ServeHTTP(rr, req)
+	return rr.Header()
+}
+
+func serveGin(t *testing.T, cfg hc.Config, req *http.Request) http.Header {
+	t.Helper()
+	r := gin.New()
+	r.Use(ginhappycontext.Middleware(cfg))
+	r.GET("/orders/:id", func(c *gin.Context) {
+		c.Header("X-Region", "eu")
+		c.Header("Set-Cookie", "sid=1")
+		_, _ = io.Copy(io.Discard, c.Request.Body)
+		c.String(http.StatusOK, "ok")
+	})
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr.Header()
+}
+
+func serveEcho(t *testing.T, cfg hc.Config, req *http.Request) http.Header {
+	t.Helper()
+	e := echo.New()
+	e.Use(echohappycontext.Middleware(cfg))
+	e.GET("/orders/:id", func(c echo.Context) error {
+		c.Response().Header().Set("X-Region", "eu")
+		c.Response().Header().Set("Set-Cookie", "sid=1")
+		_, _ = io.Copy(io.Discard, c.Request().Body)
+		return c.String(http.StatusOK, "ok")
+	})
+	rr := httptest.NewRecorder()
+	e.ServeHTTP(rr, req)
+	return rr.Header()
+}
+
+func serveFiber(t *testing.T, cfg hc.Config, req *http.Request) http.Header {
+	t.Helper()
+	app := fiber.New()
+	app.Use(fiberhappycontext.Middleware(cfg))
+	app.Get("/orders/:id", func(c *fiber.Ctx) error {
+		c.Set("X-Region", "eu")
+		c.Set("Set-Cookie", "sid=1")
+		return c.SendString("ok")
+	})
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("fiber test request: %v", err)
+	}
+	_ = resp.Body.Close()
+	return resp.Header
+}
+
+func serveFiberV3(t *testing.T, cfg hc.Config, req *http.Request) http.Header {
+	t.Helper()
+	app := fiberv3.New()
+	app.Use(fiberv3happycontext.Middleware(cfg))
+	app.Get("/orders/:id", func(c fiberv3.Ctx) error {
+		c.Set("X-Region", "eu")
+		c.Set("Set-Cookie", "sid=1")
+		return c.SendString("ok")
+	})
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("fiber v3 test request: %v", err)
+	}
+	_ = resp.Body.Close()
+	return resp.Header
+}
+
 func onlyEvent(t *testing.T, sink *hc.TestSink) hc.CapturedEvent {
 	t.Helper()
 	events := sink.Events()
@@ -19,6 +19,17 @@ func NewContext(ctx context.Context) (context.Context, *Event) {
 	return context.WithValue(ctx, contextKey{}, e), e
 }
 
+// NewContextWithConfig is like NewContext but applies the event options of
+// cfg, ErrorEncoder, CaptureErrorStacks and BreadcrumbLimit, to the new event. Later changes
+// to cfg do not affect it.
+func NewContextWithConfig(ctx context.Context, cfg Config) (context.Context, *Event) {
+	e := newEvent()
+	e.errorEncoder = cfg.ErrorEncoder
+	e.errorStacks = cfg.CaptureErrorStacks
+	e.breadcrumbLimit = cfg.BreadcrumbLimit
+	return context.WithValue(ctx, contextKey{}, e), e
+}
+
 // Add records one or more fields on the event stored in ctx.
 //
 // Additional key/value pairs can be passed via kv:
@@ -32,13 +43,44 @@ func Add(ctx context.Context, key string, value any, kv ...any) bool {
 	return e.addKV(key, value, kv...)
 }
 
+// AddFields records typed fields on the event stored in ctx.
+//
+// Unlike Add, values built with the typed constructors such as String and
+// Int64 are stored without boxing:
+// AddFields(ctx, hc.String("user_id", id), hc.Int("attempts", n)).
+func AddFields(ctx context.Context, fields ...Field) bool {
+	e := FromContext(ctx)
+	if e == nil {
+		return false
+	}
+	if len(fields) > 0 {
+		e.addFields(fields)
+	}
+	return true
+}
+
 // Error records err on the event stored in ctx.
-func Error(ctx context.Context, err error) bool {
+//
+// The error field holds the latest error's message and type, its unwrapped
+// cause chain, the members of joined errors, and, when
+// Config.CaptureErrorStacks is enabled, the stack of the Error call. When a
+// request records several errors, the errors field lists the first 10 of
+// them.
+//
+// class optionally overrides the class from ClassifyError:
+// Error(ctx, err, hc.ErrorClassClient). The event takes the most severe class
+// among its errors, which integrations map to a level with
+// Config.ErrorLevels.
+func Error(ctx context.Context, err error, class ...ErrorClass) bool {
 	e := FromContext(ctx)
 	if e == nil {
 		return false
 	}
-	e.setError(err)
+	c, _ := ClassifyError(err)
+	if len(class) > 0 {
+		c = class[0]
+	}
+	e.setError(err, c, 1)
 	return true
 }
 
@@ -72,6 +114,59 @@ func SetRoute(ctx context.Context, route string) bool {
 	return true
 }
 
+// KeepEvent forces the event in ctx to be written regardless of sampling.
+//
+// The decision and reason are recorded as sampling.decision and
+// sampling.reason. A later KeepEvent or DropEvent call replaces it.
+func KeepEvent(ctx context.Context, reason string) bool {
+	e := FromContext(ctx)
+	if e == nil {
+		return false
+	}
+	e.force(ForceKeep, reason)
+	return true
+}
+
+// DropEvent forces the event in ctx to be discarded regardless of sampling,
+// including errored requests. A later KeepEvent or DropEvent call replaces it.
+func DropEvent(ctx context.Context, reason string) bool {
+	e := FromContext(ctx)
+	if e == nil {
+		return false
+	}
+	e.force(ForceDrop, reason)
+	return true
+}
+
+// FinalizeFunc observes an event's final level and fields.
+// fields is shared between finalizers and must not be modified or retained.
+type FinalizeFunc func(level Level, fields map[string]any)
+
+// OnFinalize registers fn to run when the event in ctx is finalized.
+//
+// Finalizers run in registration order once the final level is resolved and
+// before the sampling decision, so they also observe events that are dropped.
+func OnFinalize(ctx context.Context, fn FinalizeFunc) bool {
+	e := FromContext(ctx)
+	if e == nil || fn == nil {
+		return false
+	}
+	e.addFinalizer(fn)
+	return true
+}
+
+// OnFinalizeOnce is like OnFinalize but registers fn at most once per event
+// for key, so helpers that may run several times per request do not stack
+// finalizers. key must be comparable; use an unexported type, as with
+// context keys. It reports whether fn was registered.
+func OnFinalizeOnce(ctx context.Context, key any, fn FinalizeFunc) bool {
+	e := FromContext(ctx)
+	if e == nil || fn == nil {
+		return false
+	}
+	return e.addFinalizerOnce(key, fn)
+}
+
 // GetLevel returns a previously requested level override from ctx.
 func GetLevel(ctx context.Context) (Level, bool) {
 	if e := FromContext(ctx); e != nil {
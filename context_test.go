@@ -101,3 +101,74 @@ func TestAddRejectsInvalidPairs(t *testing.T) {
 		t.Fatalf("expected no writes on invalid input, got %#v", fields)
 	}
 }
+
+func TestKeepAndDropEventRecordDecision(t *testing.T) {
+	if KeepEvent(context.Background(), "x") || DropEvent(context.Background(), "x") {
+		t.Fatal("expected forced decisions without event to return false")
+	}
+
+	ctx, e := NewContext(context.Background())
+	if !DropEvent(ctx, "noisy retry") {
+		t.Fatal("expected DropEvent to succeed")
+	}
+	if !KeepEvent(ctx, "payment anomaly") {
+		t.Fatal("expected KeepEvent to succeed")
+	}
+
+	decision, reason := EventForcedDecision(e)
+	if decision != ForceKeep || reason != "payment anomaly" {
+		t.Fatalf("decision = %v (%q), want keep", decision, reason)
+	}
+	fields := EventFields(e)
+	if fields["sampling.decision"] != "keep" || fields["sampling.reason"] != "payment anomaly" {
+		t.Fatalf("unexpected sampling fields: %v", fields)
+	}
+	if decision, _ := EventForcedDecision(nil); decision != ForceNone {
+		t.Fatalf("nil event decision = %v, want none", decision)
+	}
+}
+
+func TestOnFinalizeOnceRegistersPerKey(t *testing.T) {
+	type keyA struct{}
+	type keyB struct{}
+	if OnFinalizeOnce(context.Background(), keyA{}, func(Level, map[string]any) {}) {
+		t.Fatal("expected OnFinalizeOnce without event to return false")
+	}
+
+	ctx, e := NewContext(context.Background())
+	calls := 0
+	fn := func(Level, map[string]any) { calls++ }
+	if !OnFinalizeOnce(ctx, keyA{}, fn) || OnFinalizeOnce(ctx, keyA{}, fn) || !OnFinalizeOnce(ctx, keyB{}, fn) {
+		t.Fatal("expected one registration per key")
+	}
+	for _, fn := range EventFinalizers(e) {
+		fn(LevelInfo, nil)
+	}
+	if calls != 2 {
+		t.Fatalf("finalizer calls = %d, want 2", calls)
+	}
+}
+
+func TestOnFinalizeRegistersInOrder(t *testing.T) {
+	if OnFinalize(context.Background(), func(Level, map[string]any) {}) {
+		t.Fatal("expected OnFinalize without event to return false")
+	}
+
+	ctx, e := NewContext(context.Background())
+	if OnFinalize(ctx, nil) {
+		t.Fatal("expected nil finalizer to be rejected")
+	}
+	var order []int
+	OnFinalize(ctx, func(Level, map[string]any) { order = append(order, 1) })
+	OnFinalize(ctx, func(Level, map[string]any) { order = append(order, 2) })
+
+	for _, fn := range EventFinalizers(e) {
+		fn(LevelInfo, nil)
+	}
+	if len(order) != 2 || order[0] != 1 || order[1] != 2 {
+		t.Fatalf("finalizer order = %v", order)
+	}
+	if EventFinalizers(nil) != nil {
+		t.Fatal("expected no finalizers for nil event")
+	}
+}
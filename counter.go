@@ -0,0 +1,157 @@
+package hc
+
+import (
+	"context"
+	"math"
+)
+
+// Inc adds delta to the integer field key on the event stored in ctx.
+//
+// A missing or non-numeric field starts from zero. A float field stays a
+// float. The update is atomic with respect to other event writes, so Inc is
+// safe to call from goroutines spawned by the handler.
+func Inc(ctx context.Context, key string, delta int64) bool {
+	e := FromContext(ctx)
+	if e == nil {
+		return false
+	}
+	e.updateNumber(key, func(cur Field, ok bool) (Field, bool) {
+		if ok && cur.Kind() == FieldKindFloat64 {
+			return Float64(key, cur.Float64Value()+float64(delta)), true
+		}
+		return Int64(key, cur.Int64Value()+delta), true
+	})
+	return true
+}
+
+// AddFloat adds delta to the float field key on the event stored in ctx.
+//
+// A missing or non-numeric field starts from zero; an integer field is
+// converted to float.
+func AddFloat(ctx context.Context, key string, delta float64) bool {
+	e := FromContext(ctx)
+	if e == nil {
+		return false
+	}
+	e.updateNumber(key, func(cur Field, _ bool) (Field, bool) {
+		return Float64(key, numberValue(cur)+delta), true
+	})
+	return true
+}
+
+// Max records value as field key on the event stored in ctx when it exceeds
+// the current value, or when the field is missing or non-numeric.
+//
+// An integer field stays an integer: a larger value with a fractional part
+// leaves it unchanged and Max reports false.
+func Max(ctx context.Context, key string, value float64) bool {
+	e := FromContext(ctx)
+	if e == nil {
+		return false
+	}
+	return e.updateNumber(key, func(cur Field, ok bool) (Field, bool) {
+		if ok && numberValue(cur) >= value {
+			return cur, true
+		}
+		return replaceNumber(cur, ok, value)
+	})
+}
+
+// Min records value as field key on the event stored in ctx when it is below
+// the current value, or when the field is missing or non-numeric.
+//
+// An integer field stays an integer: a smaller value with a fractional part
+// leaves it unchanged and Min reports false.
+func Min(ctx context.Context, key string, value float64) bool {
+	e := FromContext(ctx)
+	if e == nil {
+		return false
+	}
+	return e.updateNumber(key, func(cur Field, ok bool) (Field, bool) {
+		if ok && numberValue(cur) <= value {
+			return cur, true
+		}
+		return replaceNumber(cur, ok, value)
+	})
+}
+
+// replaceNumber returns value as a field of cur's kind, or a float field when
+// cur is not numeric. It reports false when an integer kind cannot hold value.
+func replaceNumber(cur Field, ok bool, value float64) (Field, bool) {
+	if !ok || cur.Kind() == FieldKindFloat64 {
+		return Float64(cur.Key, value), true
+	}
+	if value != math.Trunc(value) || value < math.MinInt64 || value >= math.MaxInt64 {
+		return cur, false
+	}
+	if cur.Kind() == FieldKindInt {
+		return Int(cur.Key, int(value)), true
+	}
+	return Int64(cur.Key, int64(value)), true
+}
+
+// updateNumber replaces field key with the result of update, which receives
+// the current value as a numeric field and whether it was numeric. The field
+// is left alone when update reports false, which updateNumber returns.
+func (e *Event) updateNumber(key string, update func(cur Field, ok bool) (Field, bool)) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	cur, ok := e.numberLocked(key)
+	next, ok := update(cur, ok)
+	if ok {
+		e.putLocked(next)
+	}
+	return ok
+}
+
+func (e *Event) numberLocked(key string) (Field, bool) {
+	if i := e.typedIndexLocked(key); i >= 0 {
+		f := e.typed[i]
+		switch f.Kind() {
+		case FieldKindInt, FieldKindInt64, FieldKindFloat64:
+			return f, true
+		}
+		return Field{Key: key}, false
+	}
+	if _, ok := e.aggregates[key]; ok {
+		return Field{Key: key}, false
+	}
+	switch v := e.fields[key].(type) {
+	case int:
+		return Int64(key, int64(v)), true
+	case int8:
+		return Int64(key, int64(v)), true
+	case int16:
+		return Int64(key, int64(v)), true
+	case int32:
+		return Int64(key, int64(v)), true
+	case int64:
+		return Int64(key, v), true
+	case uint:
+		return Int64(key, int64(v)), true
+	case uint8:
+		return Int64(key, int64(v)), true
+	case uint16:
+		return Int64(key, int64(v)), true
+	case uint32:
+		return Int64(key, int64(v)), true
+	case uint64:
+		return Int64(key, int64(v)), true
+	case float32:
+		return Float64(key, float64(v)), true
+	case float64:
+		return Float64(key, v), true
+	}
+	return Field{Key: key}, false
+}
+
+// numberValue returns a numeric field's value as float64, or 0.
+func numberValue(f Field) float64 {
+	switch f.Kind() {
+	case FieldKindInt, FieldKindInt64:
+		return float64(f.Int64Value())
+	case FieldKindFloat64:
+		return f.Float64Value()
+	}
+	return 0
+}
@@ -0,0 +1,129 @@
+package hc
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestIncAccumulates(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	Inc(ctx, "cache.hits", 1)
+	Inc(ctx, "cache.hits", 2)
+	Add(ctx, "rows", 10)
+	Inc(ctx, "rows", 5)
+	Add(ctx, "label", "x")
+	Inc(ctx, "label", 1)
+	AddFloat(ctx, "ratio", 0.5)
+	Inc(ctx, "ratio", 1)
+
+	fields := EventFields(e)
+	if fields["cache.hits"] != int64(3) {
+		t.Fatalf("cache.hits = %#v, want 3", fields["cache.hits"])
+	}
+	if fields["rows"] != int64(15) {
+		t.Fatalf("rows = %#v, want 15", fields["rows"])
+	}
+	if fields["label"] != int64(1) {
+		t.Fatalf("label = %#v, want 1", fields["label"])
+	}
+	if fields["ratio"] != 1.5 {
+		t.Fatalf("ratio = %#v, want 1.5", fields["ratio"])
+	}
+}
+
+func TestAddFloatConvertsIntegers(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	AddFields(ctx, Int("bytes", 3))
+	AddFloat(ctx, "bytes", 0.25)
+	AddFloat(ctx, "cost", 1.5)
+	AddFloat(ctx, "cost", 1.5)
+
+	fields := EventFields(e)
+	if fields["bytes"] != 3.25 || fields["cost"] != 3.0 {
+		t.Fatalf("unexpected fields: %#v", fields)
+	}
+}
+
+func TestMaxAndMin(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	for _, v := range []float64{4, 9, 2, 7} {
+		Max(ctx, "depth.max", v)
+		Min(ctx, "depth.min", v)
+	}
+	Add(ctx, "queue", 5)
+	Max(ctx, "queue", 3)
+	Add(ctx, "name", "x")
+	Min(ctx, "name", 8)
+
+	fields := EventFields(e)
+	if fields["depth.max"] != 9.0 || fields["depth.min"] != 2.0 {
+		t.Fatalf("unexpected max/min: %#v", fields)
+	}
+	if fields["queue"] != int64(5) {
+		t.Fatalf("queue = %#v, want existing 5", fields["queue"])
+	}
+	if fields["name"] != 8.0 {
+		t.Fatalf("name = %#v, want 8", fields["name"])
+	}
+}
+
+func TestMaxAndMinKeepIntegerKind(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	AddFields(ctx, Int("retries", 2))
+	Add(ctx, "queue", 5)
+
+	if !Max(ctx, "retries", 4) || !Min(ctx, "queue", 3) {
+		t.Fatal("expected whole values to update integer fields")
+	}
+	if Max(ctx, "retries", 4.5) || Min(ctx, "queue", 2.5) {
+		t.Fatal("expected fractional values to be rejected on integer fields")
+	}
+	if !Max(ctx, "retries", 1.5) {
+		t.Fatal("expected a value that does not win to leave the field and report true")
+	}
+
+	fields := EventFields(e)
+	if fields["retries"] != 4 || fields["queue"] != int64(3) {
+		t.Fatalf("expected integer kinds kept, got %#v / %#v", fields["retries"], fields["queue"])
+	}
+}
+
+func TestCountersNoopWithoutEvent(t *testing.T) {
+	ctx := context.Background()
+	if Inc(ctx, "a", 1) || AddFloat(ctx, "a", 1) || Max(ctx, "a", 1) || Min(ctx, "a", 1) {
+		t.Fatal("expected counters without event to report false")
+	}
+}
+
+func TestIncConcurrent(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				Inc(ctx, "n", 1)
+				AddFloat(ctx, "sum", 0.5)
+			}
+		}()
+	}
+	wg.Wait()
+
+	fields := EventFields(e)
+	if fields["n"] != int64(800) || fields["sum"] != 400.0 {
+		t.Fatalf("unexpected totals: %#v", fields)
+	}
+}
+
+func TestIncDoesNotAllocate(t *testing.T) {
+	ctx, _ := NewContext(context.Background())
+	Inc(ctx, "n", 1)
+	allocs := testing.AllocsPerRun(100, func() {
+		Inc(ctx, "n", 1)
+	})
+	if allocs != 0 {
+		t.Fatalf("Inc allocated %v times per call", allocs)
+	}
+}
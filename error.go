@@ -0,0 +1,99 @@
+package hc
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+)
+
+const (
+	errorField       = "error"
+	errorsField      = "errors"
+	maxErrors        = 10
+	maxErrorChain    = 8
+	maxErrorDepth    = 3
+	maxErrorJoined   = 8
+	maxErrorStackLen = 32
+)
+
+// ErrorEncoder adds fields extracted from err, such as a domain error code,
+// to entry. It runs for every recorded error and for each error in its
+// cause chain. entry already holds message and type.
+type ErrorEncoder func(err error, entry map[string]any)
+
+// encodeError renders err as an error entry holding message and type, the
+// unwrapped cause chain, and the members of joined errors. enc may be nil.
+func encodeError(err error, enc ErrorEncoder, depth int) map[string]any {
+	entry := errorEntry(err, enc)
+	if depth >= maxErrorDepth {
+		return entry
+	}
+	if joined := joinedErrors(err); joined != nil {
+		entry["errors"] = encodeJoined(joined, enc, depth)
+		return entry
+	}
+
+	var chain []any
+	for cause := errors.Unwrap(err); cause != nil && len(chain) < maxErrorChain; cause = errors.Unwrap(cause) {
+		c := errorEntry(cause, enc)
+		chain = append(chain, c)
+		if joined := joinedErrors(cause); joined != nil {
+			c["errors"] = encodeJoined(joined, enc, depth)
+			break
+		}
+	}
+	if len(chain) > 0 {
+		entry["chain"] = chain
+	}
+	return entry
+}
+
+func errorEntry(err error, enc ErrorEncoder) map[string]any {
+	entry := map[string]any{
+		"message": err.Error(),
+		"type":    fmt.Sprintf("%T", err),
+	}
+	if enc != nil {
+		enc(err, entry)
+	}
+	return entry
+}
+
+func joinedErrors(err error) []error {
+	if u, ok := err.(interface{ Unwrap() []error }); ok {
+		return u.Unwrap()
+	}
+	return nil
+}
+
+func encodeJoined(errs []error, enc ErrorEncoder, depth int) []any {
+	out := make([]any, 0, min(len(errs), maxErrorJoined))
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if len(out) == maxErrorJoined {
+			break
+		}
+		out = append(out, encodeError(err, enc, depth+1))
+	}
+	return out
+}
+
+// callerStack returns up to maxErrorStackLen frames as "function (file:line)",
+// omitting the first skip frames, where 0 is callerStack's caller.
+func callerStack(skip int) []any {
+	var pcs [maxErrorStackLen]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	stack := make([]any, 0, n)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, frame.Function+" ("+frame.File+":"+strconv.Itoa(frame.Line)+")")
+		if !more {
+			break
+		}
+	}
+	return stack
+}
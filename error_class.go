@@ -0,0 +1,77 @@
+package hc
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrorClass classifies an error by who is at fault, which decides the
+// event's level and whether the event bypasses sampling.
+type ErrorClass uint8
+
+const (
+	// ErrorClassServer is a fault in the service. It is the default class.
+	ErrorClassServer ErrorClass = iota
+	// ErrorClassClient is a fault in the request, such as failed validation.
+	ErrorClassClient
+	// ErrorClassTransient is a temporary failure, such as a timeout or an
+	// unavailable dependency.
+	ErrorClassTransient
+	// ErrorClassCanceled is a request abandoned by its caller.
+	ErrorClassCanceled
+)
+
+// String returns the field value recorded for c.
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassClient:
+		return "client"
+	case ErrorClassTransient:
+		return "transient"
+	case ErrorClassCanceled:
+		return "canceled"
+	default:
+		return "server"
+	}
+}
+
+// ErrorClassifier is implemented by errors that know their class.
+// Wrapped errors are classified by the first classifier in their chain.
+type ErrorClassifier interface {
+	ErrorClass() ErrorClass
+}
+
+// ClassifyError returns the class of err and whether err carried one.
+//
+// Errors implementing ErrorClassifier report their own class;
+// context.Canceled is ErrorClassCanceled and context.DeadlineExceeded is
+// ErrorClassTransient. Other errors are ErrorClassServer, with ok false.
+func ClassifyError(err error) (class ErrorClass, ok bool) {
+	var classifier ErrorClassifier
+	switch {
+	case err == nil:
+		return ErrorClassServer, false
+	case errors.As(err, &classifier):
+		return classifier.ErrorClass(), true
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled, true
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTransient, true
+	}
+	return ErrorClassServer, false
+}
+
+// errorClassRank orders classes by severity, so an event with several
+// errors takes the class of its most severe one.
+func errorClassRank(c ErrorClass) int {
+	switch c {
+	case ErrorClassCanceled:
+		return 0
+	case ErrorClassClient:
+		return 1
+	case ErrorClassTransient:
+		return 2
+	default:
+		return 3
+	}
+}
@@ -0,0 +1,68 @@
+package hc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type validationError struct{}
+
+func (validationError) Error() string          { return "invalid email" }
+func (validationError) ErrorClass() ErrorClass { return ErrorClassClient }
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		class ErrorClass
+		ok    bool
+	}{
+		{"nil", nil, ErrorClassServer, false},
+		{"plain", errors.New("boom"), ErrorClassServer, false},
+		{"classifier", fmt.Errorf("signup: %w", validationError{}), ErrorClassClient, true},
+		{"canceled", fmt.Errorf("query: %w", context.Canceled), ErrorClassCanceled, true},
+		{"deadline", context.DeadlineExceeded, ErrorClassTransient, true},
+	}
+	for _, tt := range tests {
+		class, ok := ClassifyError(tt.err)
+		if class != tt.class || ok != tt.ok {
+			t.Fatalf("%s: got (%s, %v), want (%s, %v)", tt.name, class, ok, tt.class, tt.ok)
+		}
+	}
+}
+
+func TestErrorRecordsClass(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	if _, ok := EventErrorClass(e); ok {
+		t.Fatal("did not expect an error class before Error")
+	}
+
+	Error(ctx, validationError{})
+	if class, ok := EventErrorClass(e); !ok || class != ErrorClassClient {
+		t.Fatalf("class = %s, want client", class)
+	}
+	if got := EventFields(e)["error"].(map[string]any)["class"]; got != "client" {
+		t.Fatalf("error.class = %v, want client", got)
+	}
+
+	Error(ctx, errors.New("bad input"), ErrorClassCanceled)
+	if class, _ := EventErrorClass(e); class != ErrorClassClient {
+		t.Fatalf("expected less severe class to keep client, got %s", class)
+	}
+
+	Error(ctx, errors.New("db down"), ErrorClassTransient)
+	if class, _ := EventErrorClass(e); class != ErrorClassTransient {
+		t.Fatalf("expected more severe class to win, got %s", class)
+	}
+	if got := EventFields(e)["error"].(map[string]any)["class"]; got != "transient" {
+		t.Fatalf("error.class = %v, want transient", got)
+	}
+}
+
+func TestEventErrorClassNilEvent(t *testing.T) {
+	if _, ok := EventErrorClass(nil); ok {
+		t.Fatal("expected no class for nil event")
+	}
+}
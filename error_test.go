@@ -0,0 +1,129 @@
+package hc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type codedError struct{ code string }
+
+func (e *codedError) Error() string { return "coded " + e.code }
+
+func TestErrorRecordsCauseChain(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	base := errors.New("connection refused")
+	Error(ctx, fmt.Errorf("charge card: %w", fmt.Errorf("call provider: %w", base)))
+
+	entry := EventFields(e)["error"].(map[string]any)
+	if entry["message"] != "charge card: call provider: connection refused" {
+		t.Fatalf("unexpected message: %v", entry["message"])
+	}
+	chain, ok := entry["chain"].([]any)
+	if !ok || len(chain) != 2 {
+		t.Fatalf("expected two causes, got %#v", entry["chain"])
+	}
+	if last := chain[1].(map[string]any); last["message"] != "connection refused" || last["type"] != "*errors.errorString" {
+		t.Fatalf("unexpected root cause: %#v", last)
+	}
+}
+
+func TestErrorExpandsJoinedErrors(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	Error(ctx, errors.Join(errors.New("a"), nil, fmt.Errorf("b: %w", errors.New("c"))))
+
+	entry := EventFields(e)["error"].(map[string]any)
+	joined, ok := entry["errors"].([]any)
+	if !ok || len(joined) != 2 {
+		t.Fatalf("expected two joined errors, got %#v", entry["errors"])
+	}
+	second := joined[1].(map[string]any)
+	if second["message"] != "b: c" {
+		t.Fatalf("unexpected joined error: %#v", second)
+	}
+	if chain := second["chain"].([]any); chain[0].(map[string]any)["message"] != "c" {
+		t.Fatalf("expected joined error chain, got %#v", chain)
+	}
+	if _, ok := entry["chain"]; ok {
+		t.Fatal("did not expect a chain on the joined error itself")
+	}
+}
+
+func TestErrorListsMultipleErrors(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	Error(ctx, errors.New("first"))
+	if _, ok := EventFields(e)["errors"]; ok {
+		t.Fatal("did not expect errors list for a single error")
+	}
+	for i := 0; i < maxErrors+1; i++ {
+		Error(ctx, fmt.Errorf("err %d", i))
+	}
+
+	fields := EventFields(e)
+	if entry := fields["error"].(map[string]any); entry["message"] != "err 10" {
+		t.Fatalf("expected latest error, got %#v", entry)
+	}
+	list := fields["errors"].([]any)
+	if len(list) != maxErrors {
+		t.Fatalf("expected %d errors, got %d", maxErrors, len(list))
+	}
+	if list[0].(map[string]any)["message"] != "first" || list[1].(map[string]any)["message"] != "err 0" {
+		t.Fatalf("unexpected errors order: %#v", list[:2])
+	}
+	if fields["errors_dropped"] != 2 {
+		t.Fatalf("errors_dropped = %#v, want 2", fields["errors_dropped"])
+	}
+}
+
+func TestErrorEncoderAddsFields(t *testing.T) {
+	ctx, e := NewContextWithConfig(context.Background(), Config{
+		ErrorEncoder: func(err error, entry map[string]any) {
+			if coded, ok := err.(*codedError); ok {
+				entry["code"] = coded.code
+			}
+		},
+	})
+	Error(ctx, fmt.Errorf("checkout: %w", &codedError{code: "card_declined"}))
+
+	entry := EventFields(e)["error"].(map[string]any)
+	if _, ok := entry["code"]; ok {
+		t.Fatal("did not expect code on the wrapping error")
+	}
+	if cause := entry["chain"].([]any)[0].(map[string]any); cause["code"] != "card_declined" {
+		t.Fatalf("expected code on cause, got %#v", cause)
+	}
+}
+
+func TestCaptureErrorStacks(t *testing.T) {
+	ctx, e := NewContextWithConfig(context.Background(), Config{CaptureErrorStacks: true})
+	Error(ctx, errors.New("boom"))
+
+	stack, ok := EventFields(e)["error"].(map[string]any)["stack"].([]any)
+	if !ok || len(stack) == 0 {
+		t.Fatal("expected captured stack")
+	}
+	if top := stack[0].(string); !strings.Contains(top, "TestCaptureErrorStacks") {
+		t.Fatalf("expected stack to start at the Error call site, got %q", top)
+	}
+}
+
+func TestErrorStackDisabledByDefault(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	Error(ctx, errors.New("boom"))
+	if _, ok := EventFields(e)["error"].(map[string]any)["stack"]; ok {
+		t.Fatal("did not expect stack by default")
+	}
+}
+
+func TestErrorRecordsSameErrorOnce(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	err := errors.New("boom")
+	Error(ctx, err)
+	Error(ctx, err)
+
+	if _, ok := EventFields(e)["errors"]; ok {
+		t.Fatal("expected repeated error to be recorded once")
+	}
+}
@@ -1,8 +1,9 @@
 package hc
 
 import (
-	"fmt"
 	"maps"
+	"reflect"
+	"slices"
 	"sync"
 	"time"
 )
@@ -12,10 +13,29 @@ type Event struct {
 	mu                sync.RWMutex
 	message           string
 	fields            map[string]any
+	typed             []Field
+	typedIndex        map[string]int
 	startTime         time.Time
 	hasError          bool
+	lastErr           error
+	errorClass        ErrorClass
+	errorEncoder      ErrorEncoder
+	errorStacks       bool
+	breadcrumbLimit   int
 	requestedLevel    Level
 	hasRequestedLevel bool
+	forced            ForcedDecision
+	forcedReason      string
+	sampleRate        float64
+	finalizers        []FinalizeFunc
+	finalizerKeys     []any
+	aggregates        map[string]aggregate
+}
+
+// aggregate is typed field state that is rendered into a fresh field value
+// on every snapshot, so values handed to sinks are never mutated later.
+type aggregate interface {
+	fieldValue() any
 }
 
 type snapshot struct {
@@ -50,13 +70,118 @@ func (e *Event) addKV(key string, value any, kv ...any) bool {
 		}
 		e.fields = make(map[string]any, capHint)
 	}
-	e.fields[key] = value
+	e.setLocked(key, value)
 	for i := 0; i < len(kv); i += 2 {
-		e.fields[kv[i].(string)] = kv[i+1]
+		e.setLocked(kv[i].(string), kv[i+1])
 	}
 	return true
 }
 
+// typedIndexThreshold is the typed field count past which lookups switch
+// from a linear scan to a key index.
+const typedIndexThreshold = 8
+
+func (e *Event) addFields(fields []Field) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.typed == nil {
+		e.typed = make([]Field, 0, max(len(fields), typedIndexThreshold))
+	}
+	for _, f := range fields {
+		e.putLocked(f)
+	}
+}
+
+// putLocked stores a typed field, replacing any untyped field or aggregate
+// under its key. e.mu must be held.
+func (e *Event) putLocked(f Field) {
+	if len(e.fields) > 0 {
+		delete(e.fields, f.Key)
+	}
+	if len(e.aggregates) > 0 {
+		delete(e.aggregates, f.Key)
+	}
+	if i := e.typedIndexLocked(f.Key); i >= 0 {
+		e.typed[i] = f
+		return
+	}
+	e.typed = append(e.typed, f)
+	switch {
+	case e.typedIndex != nil:
+		e.typedIndex[f.Key] = len(e.typed) - 1
+	case len(e.typed) > typedIndexThreshold:
+		e.typedIndex = make(map[string]int, 2*len(e.typed))
+		for i := range e.typed {
+			e.typedIndex[e.typed[i].Key] = i
+		}
+	}
+}
+
+// setLocked stores an untyped field, replacing any typed field or aggregate
+// under key. e.mu must be held and e.fields non-nil.
+func (e *Event) setLocked(key string, value any) {
+	e.fields[key] = value
+	if len(e.aggregates) > 0 {
+		delete(e.aggregates, key)
+	}
+	if len(e.typed) > 0 {
+		if i := e.typedIndexLocked(key); i >= 0 {
+			e.removeTypedLocked(i)
+		}
+	}
+}
+
+func (e *Event) typedIndexLocked(key string) int {
+	if e.typedIndex != nil {
+		if i, ok := e.typedIndex[key]; ok {
+			return i
+		}
+		return -1
+	}
+	for i := range e.typed {
+		if e.typed[i].Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// removeTypedLocked removes typed[i] by moving the last field into its slot.
+func (e *Event) removeTypedLocked(i int) {
+	last := len(e.typed) - 1
+	if e.typedIndex != nil {
+		delete(e.typedIndex, e.typed[i].Key)
+		if i != last {
+			e.typedIndex[e.typed[last].Key] = i
+		}
+	}
+	e.typed[i] = e.typed[last]
+	e.typed[last] = Field{}
+	e.typed = e.typed[:last]
+}
+
+// updateAggregate runs update on the aggregate stored under key, creating it
+// with create when missing or when key holds a different aggregate type.
+func updateAggregate[T aggregate](e *Event, key string, create func() T, update func(T)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	update(aggregateLocked(e, key, create))
+}
+
+// aggregateLocked returns the aggregate stored under key, creating it with
+// create when missing or of a different type. e.mu must be held.
+func aggregateLocked[T aggregate](e *Event, key string, create func() T) T {
+	if e.aggregates == nil {
+		e.aggregates = make(map[string]aggregate, 2)
+	}
+	a, ok := e.aggregates[key].(T)
+	if !ok {
+		a = create()
+		e.aggregates[key] = a
+	}
+	return a
+}
+
 func (e *Event) setRoute(route string) {
 	if route == "" {
 		return
@@ -65,24 +190,57 @@ func (e *Event) setRoute(route string) {
 	if e.fields == nil {
 		e.fields = make(map[string]any, 8)
 	}
-	e.fields["http.route"] = route
+	e.setLocked("http.route", route)
 	e.mu.Unlock()
 }
 
-func (e *Event) setError(err error) {
-	if err == nil {
+// setError records err as the error field. Once a second error arrives, every
+// recorded error is also listed in the errors field. skip is the number of
+// callers of setError to omit from a captured stack.
+func (e *Event) setError(err error, class ErrorClass, skip int) {
+	if err == nil || e.isLastError(err) {
 		return
 	}
+	// errorEncoder and errorStacks are fixed when the event is created.
+	entry := encodeError(err, e.errorEncoder, 0)
+	entry["class"] = class.String()
+	if e.errorStacks {
+		entry["stack"] = callerStack(skip + 1)
+	}
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	if e.fields == nil {
 		e.fields = make(map[string]any, 8)
 	}
+	if e.hasError {
+		l := aggregateLocked(e, errorsField, newValueList)
+		if len(l.values) == 0 && l.dropped == 0 {
+			if prev, ok := e.fields[errorField].(map[string]any); ok {
+				l.append([]any{prev}, maxErrors)
+			}
+		}
+		if l.append([]any{entry}, maxErrors) {
+			e.putLocked(Int(errorsField+droppedSuffix, l.dropped))
+		}
+	}
+	if !e.hasError || errorClassRank(class) > errorClassRank(e.errorClass) {
+		e.errorClass = class
+	}
 	e.hasError = true
-	e.fields["error"] = map[string]any{
-		"message": err.Error(),
-		"type":    fmt.Sprintf("%T", err),
+	e.lastErr = err
+	e.setLocked(errorField, entry)
+}
+
+// isLastError reports whether err is the error recorded last, so an error
+// passed to Error and then returned to an integration is listed once.
+func (e *Event) isLastError(err error) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.lastErr == nil || !reflect.TypeOf(err).Comparable() {
+		return false
 	}
+	return e.lastErr == err
 }
 
 func (e *Event) setMessage(msg string) {
@@ -91,6 +249,12 @@ func (e *Event) setMessage(msg string) {
 	e.message = msg
 }
 
+func (e *Event) errorClassValue() (ErrorClass, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.errorClass, e.hasError
+}
+
 func (e *Event) hasErrorValue() bool {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
@@ -126,17 +290,120 @@ func (e *Event) requestedLevelValue() (Level, bool) {
 	return e.requestedLevel, e.hasRequestedLevel
 }
 
+func (e *Event) force(decision ForcedDecision, reason string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.fields == nil {
+		e.fields = make(map[string]any, 8)
+	}
+	e.forced = decision
+	e.forcedReason = reason
+	e.setLocked("sampling.decision", decision.String())
+	e.setLocked("sampling.reason", reason)
+}
+
+func (e *Event) forcedDecision() (ForcedDecision, string) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.forced, e.forcedReason
+}
+
+func (e *Event) setSampleRate(rate float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sampleRate = rate
+}
+
+func (e *Event) sampleRateValue() (float64, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.sampleRate, e.sampleRate > 0
+}
+
+func (e *Event) addFinalizer(fn FinalizeFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.finalizers = append(e.finalizers, fn)
+}
+
+// addFinalizerOnce adds fn unless a finalizer was already added under key.
+func (e *Event) addFinalizerOnce(key any, fn FinalizeFunc) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if slices.Contains(e.finalizerKeys, key) {
+		return false
+	}
+	e.finalizerKeys = append(e.finalizerKeys, key)
+	e.finalizers = append(e.finalizers, fn)
+	return true
+}
+
+func (e *Event) finalizersValue() []FinalizeFunc {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return slices.Clone(e.finalizers)
+}
+
+func (e *Event) lookup(key string) (any, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if a, ok := e.aggregates[key]; ok {
+		return a.fieldValue(), true
+	}
+	if i := e.typedIndexLocked(key); i >= 0 {
+		return e.typed[i].Value(), true
+	}
+	v, ok := e.fields[key]
+	return v, ok
+}
+
 func (e *Event) snapshot() snapshot {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
+	var fields map[string]any
+	if n := len(e.fields) + len(e.typed) + len(e.aggregates); n > 0 {
+		// The extra slot lets callers add sample_rate without growing.
+		fields = make(map[string]any, n+1)
+		maps.Copy(fields, e.fields)
+		for _, f := range e.typed {
+			fields[f.Key] = f.Value()
+		}
+		for key, a := range e.aggregates {
+			fields[key] = a.fieldValue()
+		}
+	}
+
 	return snapshot{
-		fields:    maps.Clone(e.fields),
+		fields:    fields,
 		startTime: e.startTime,
 		hasError:  e.hasError,
 	}
 }
 
+// appendFieldList appends every field to dst as a Field, keeping typed
+// fields unboxed.
+func (e *Event) appendFieldList(dst []Field) []Field {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	dst = slices.Grow(dst, len(e.typed)+len(e.fields)+len(e.aggregates)+1)
+	for _, f := range e.typed {
+		if _, ok := e.aggregates[f.Key]; !ok {
+			dst = append(dst, f)
+		}
+	}
+	for key, value := range e.fields {
+		if _, ok := e.aggregates[key]; !ok {
+			dst = append(dst, Any(key, value))
+		}
+	}
+	for key, a := range e.aggregates {
+		dst = append(dst, Any(key, a.fieldValue()))
+	}
+	return dst
+}
+
 func (e *Event) getMessage() string {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
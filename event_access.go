@@ -11,6 +11,21 @@ func EventFields(e *Event) map[string]any {
 	return e.snapshot().fields
 }
 
+// EventFieldList returns e's fields as a new slice of Field.
+// Fields recorded with AddFields keep their type; others are FieldKindAny.
+func EventFieldList(e *Event) []Field {
+	return EventAppendFieldList(e, nil)
+}
+
+// EventAppendFieldList is like EventFieldList but appends to dst, so callers
+// can reuse buffers across events.
+func EventAppendFieldList(e *Event, dst []Field) []Field {
+	if e == nil {
+		return dst
+	}
+	return e.appendFieldList(dst)
+}
+
 // EventMessage returns e's attached message, or an empty string if unset.
 func EventMessage(e *Event) string {
 	if e == nil {
@@ -27,6 +42,15 @@ func EventHasError(e *Event) bool {
 	return e.hasErrorValue()
 }
 
+// EventErrorClass returns the class of e's most severe error and whether e
+// has an attached error.
+func EventErrorClass(e *Event) (ErrorClass, bool) {
+	if e == nil {
+		return ErrorClassServer, false
+	}
+	return e.errorClassValue()
+}
+
 // EventHasMessage reports whether e has an attached message.
 func EventHasMessage(e *Event) bool {
 	if e == nil {
@@ -42,3 +66,28 @@ func EventStartTime(e *Event) time.Time {
 	}
 	return e.startedAt()
 }
+
+// EventForcedDecision returns the forced sampling decision on e and its reason.
+func EventForcedDecision(e *Event) (ForcedDecision, string) {
+	if e == nil {
+		return ForceNone, ""
+	}
+	return e.forcedDecision()
+}
+
+// EventSampleRate returns the sample rate a sampler reported for e.
+// It returns false when no sampler reported a rate.
+func EventSampleRate(e *Event) (float64, bool) {
+	if e == nil {
+		return 0, false
+	}
+	return e.sampleRateValue()
+}
+
+// EventFinalizers returns the finalizers registered on e with OnFinalize.
+func EventFinalizers(e *Event) []FinalizeFunc {
+	if e == nil {
+		return nil
+	}
+	return e.finalizersValue()
+}
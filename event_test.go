@@ -30,7 +30,7 @@ func TestEventConcurrentAdd(t *testing.T) {
 
 func TestSetError(t *testing.T) {
 	e := newEvent()
-	e.setError(errors.New("x"))
+	e.setError(errors.New("x"), ErrorClassServer, 0)
 
 	s := e.snapshot()
 	if !s.hasError {
@@ -47,7 +47,7 @@ func TestSetError(t *testing.T) {
 
 func TestSetErrorNilDoesNothing(t *testing.T) {
 	e := newEvent()
-	e.setError(nil)
+	e.setError(nil, ErrorClassServer, 0)
 	if e.hasErrorValue() {
 		t.Fatalf("expected HasError=false")
 	}
@@ -69,7 +69,7 @@ func TestEventStartTimeAndHasError(t *testing.T) {
 	if e.startedAt().IsZero() {
 		t.Fatalf("expected non-zero start time")
 	}
-	e.setError(errors.New("boom"))
+	e.setError(errors.New("boom"), ErrorClassServer, 0)
 	if !e.hasErrorValue() {
 		t.Fatalf("expected has error")
 	}
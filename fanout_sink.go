@@ -0,0 +1,83 @@
+package hc
+
+import (
+	"maps"
+	"sync/atomic"
+)
+
+// Destination is one child sink of a FanoutSink.
+type Destination struct {
+	// Sink receives matching events.
+	Sink Sink
+
+	// MinLevel drops events below this level. An empty level accepts every event.
+	MinLevel Level
+
+	// Filter optionally accepts or rejects events by level and fields.
+	// Return true to write the event to Sink.
+	Filter func(level Level, fields map[string]any) bool
+}
+
+// FanoutSink forwards each event to several destinations.
+//
+// A destination that panics is isolated: the panic is recovered and counted,
+// and the remaining destinations still receive the event.
+type FanoutSink struct {
+	destinations []Destination
+	panics       atomic.Uint64
+}
+
+// NewFanoutSink returns a sink writing to every destination with a non-nil Sink.
+func NewFanoutSink(destinations ...Destination) *FanoutSink {
+	filtered := make([]Destination, 0, len(destinations))
+	for _, d := range destinations {
+		if d.Sink != nil {
+			filtered = append(filtered, d)
+		}
+	}
+	return &FanoutSink{destinations: filtered}
+}
+
+// Write implements Sink.
+//
+// With more than one destination, each one receives its own top-level copy
+// of fields so a destination mutating the map cannot affect the others.
+func (f *FanoutSink) Write(level Level, message string, fields map[string]any) {
+	if f == nil {
+		return
+	}
+	shared := len(f.destinations) > 1
+	for i := range f.destinations {
+		d := &f.destinations[i]
+		if isValidLevel(d.MinLevel) && levelRank(level) < levelRank(d.MinLevel) {
+			continue
+		}
+		out := fields
+		if shared {
+			out = maps.Clone(fields)
+		}
+		f.write(d, level, message, out)
+	}
+}
+
+// Panics returns how many destination writes panicked.
+func (f *FanoutSink) Panics() uint64 {
+	if f == nil {
+		return 0
+	}
+	return f.panics.Load()
+}
+
+func (f *FanoutSink) write(d *Destination, level Level, message string, fields map[string]any) {
+	defer func() {
+		if recover() != nil {
+			f.panics.Add(1)
+		}
+	}()
+	if d.Filter != nil && !d.Filter(level, fields) {
+		return
+	}
+	d.Sink.Write(level, message, fields)
+}
+
+var _ Sink = (*FanoutSink)(nil)
@@ -0,0 +1,84 @@
+package hc
+
+import "testing"
+
+func TestFanoutSinkAppliesMinLevelAndFilter(t *testing.T) {
+	all := NewTestSink()
+	errorsOnly := NewTestSink()
+	checkout := NewTestSink()
+	sink := NewFanoutSink(
+		Destination{Sink: all},
+		Destination{Sink: errorsOnly, MinLevel: LevelError},
+		Destination{Sink: checkout, Filter: func(_ Level, fields map[string]any) bool {
+			return fields["http.route"] == "/checkout"
+		}},
+		Destination{},
+	)
+
+	sink.Write(LevelInfo, "ok", map[string]any{"http.route": "/checkout"})
+	sink.Write(LevelError, "failed", map[string]any{"http.route": "/orders"})
+
+	if got := len(all.Events()); got != 2 {
+		t.Fatalf("all destination got %d events, want 2", got)
+	}
+	errs := errorsOnly.Events()
+	if len(errs) != 1 || errs[0].Message != "failed" {
+		t.Fatalf("unexpected error destination events: %+v", errs)
+	}
+	routed := checkout.Events()
+	if len(routed) != 1 || routed[0].Message != "ok" {
+		t.Fatalf("unexpected filtered destination events: %+v", routed)
+	}
+}
+
+func TestFanoutSinkIsolatesPanickingDestination(t *testing.T) {
+	after := NewTestSink()
+	sink := NewFanoutSink(
+		Destination{Sink: panicSink{}},
+		Destination{Sink: NewTestSink(), Filter: func(Level, map[string]any) bool { panic("filter") }},
+		Destination{Sink: after},
+	)
+
+	sink.Write(LevelInfo, "ok", map[string]any{"k": "v"})
+
+	if got := len(after.Events()); got != 1 {
+		t.Fatalf("expected healthy destination to receive event, got %d", got)
+	}
+	if got := sink.Panics(); got != 2 {
+		t.Fatalf("panics = %d, want 2", got)
+	}
+}
+
+func TestFanoutSinkCopiesFieldsPerDestination(t *testing.T) {
+	observer := NewTestSink()
+	sink := NewFanoutSink(
+		Destination{Sink: mutatingSink{}},
+		Destination{Sink: observer},
+	)
+	fields := map[string]any{"k": "v"}
+
+	sink.Write(LevelInfo, "ok", fields)
+
+	if observer.Events()[0].Fields["k"] != "v" {
+		t.Fatal("expected destination to be isolated from mutations by another destination")
+	}
+	if fields["k"] != "v" {
+		t.Fatal("expected caller fields to be untouched")
+	}
+}
+
+func TestFanoutSinkNilSafe(t *testing.T) {
+	var sink *FanoutSink
+	sink.Write(LevelInfo, "ok", nil)
+	if sink.Panics() != 0 {
+		t.Fatal("expected zero panics on nil sink")
+	}
+}
+
+type panicSink struct{}
+
+func (panicSink) Write(Level, string, map[string]any) { panic("sink failed") }
+
+type mutatingSink struct{}
+
+func (mutatingSink) Write(_ Level, _ string, fields map[string]any) { fields["k"] = "mutated" }
@@ -0,0 +1,155 @@
+package hc
+
+import (
+	"math"
+	"time"
+	"unsafe"
+)
+
+// FieldKind identifies the type stored in a Field.
+type FieldKind uint8
+
+const (
+	// FieldKindAny holds an arbitrary value.
+	FieldKindAny FieldKind = iota
+	// FieldKindString holds a string.
+	FieldKindString
+	// FieldKindInt holds an int.
+	FieldKindInt
+	// FieldKindInt64 holds an int64.
+	FieldKindInt64
+	// FieldKindFloat64 holds a float64.
+	FieldKindFloat64
+	// FieldKindBool holds a bool.
+	FieldKindBool
+	// FieldKindDuration holds a time.Duration.
+	FieldKindDuration
+	// FieldKindTime holds a time.Time.
+	FieldKindTime
+)
+
+// kindTag marks a typed Field in its value slot. Boxing a one-byte value does
+// not allocate, and no caller can pass one to Any.
+type kindTag FieldKind
+
+// Field is a typed key/value pair.
+//
+// Fields built with the typed constructors store their value without boxing
+// it into an interface, so recording them with AddFields avoids the
+// per-value allocations of Add. Field is a tagged union: typed fields keep
+// their kind in val and their value in num (numeric bits, a string's length,
+// or a time's UnixNano) and ptr (a string's data or a time's location), while
+// FieldKindAny fields keep their value in val.
+type Field struct {
+	Key string
+	num uint64
+	ptr unsafe.Pointer
+	val any
+}
+
+// String returns a string Field.
+func String(key, value string) Field {
+	return Field{Key: key, num: uint64(len(value)), ptr: unsafe.Pointer(unsafe.StringData(value)), val: kindTag(FieldKindString)}
+}
+
+// Int returns an int Field.
+func Int(key string, value int) Field {
+	return Field{Key: key, num: uint64(value), val: kindTag(FieldKindInt)}
+}
+
+// Int64 returns an int64 Field.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, num: uint64(value), val: kindTag(FieldKindInt64)}
+}
+
+// Float64 returns a float64 Field.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, num: math.Float64bits(value), val: kindTag(FieldKindFloat64)}
+}
+
+// Bool returns a bool Field.
+func Bool(key string, value bool) Field {
+	f := Field{Key: key, val: kindTag(FieldKindBool)}
+	if value {
+		f.num = 1
+	}
+	return f
+}
+
+// Duration returns a time.Duration Field.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, num: uint64(value), val: kindTag(FieldKindDuration)}
+}
+
+// Time returns a time.Time Field. The monotonic clock reading is dropped.
+// Times outside the range of UnixNano are stored as FieldKindAny.
+func Time(key string, value time.Time) Field {
+	if y := value.Year(); y < 1678 || y > 2261 {
+		return Any(key, value)
+	}
+	return Field{Key: key, num: uint64(value.UnixNano()), ptr: unsafe.Pointer(value.Location()), val: kindTag(FieldKindTime)}
+}
+
+// Any returns a Field holding an arbitrary value.
+func Any(key string, value any) Field {
+	return Field{Key: key, val: value}
+}
+
+// Kind returns the type stored in f.
+func (f Field) Kind() FieldKind {
+	if k, ok := f.val.(kindTag); ok {
+		return FieldKind(k)
+	}
+	return FieldKindAny
+}
+
+// StringValue returns the value of a FieldKindString field.
+func (f Field) StringValue() string {
+	if f.Kind() != FieldKindString {
+		return ""
+	}
+	return unsafe.String((*byte)(f.ptr), int(f.num))
+}
+
+// Int64Value returns the value of a FieldKindInt or FieldKindInt64 field.
+func (f Field) Int64Value() int64 { return int64(f.num) }
+
+// Float64Value returns the value of a FieldKindFloat64 field.
+func (f Field) Float64Value() float64 { return math.Float64frombits(f.num) }
+
+// BoolValue returns the value of a FieldKindBool field.
+func (f Field) BoolValue() bool { return f.num != 0 }
+
+// DurationValue returns the value of a FieldKindDuration field.
+func (f Field) DurationValue() time.Duration { return time.Duration(f.num) }
+
+// TimeValue returns the value of a FieldKindTime field.
+func (f Field) TimeValue() time.Time {
+	loc := (*time.Location)(f.ptr)
+	if f.Kind() != FieldKindTime || loc == nil {
+		loc = time.UTC
+	}
+	return time.Unix(0, int64(f.num)).In(loc)
+}
+
+// Value returns f's value boxed into an interface, with its original type.
+func (f Field) Value() any {
+	switch f.Kind() {
+	case FieldKindString:
+		return f.StringValue()
+	case FieldKindInt:
+		return int(f.num)
+	case FieldKindInt64:
+		return int64(f.num)
+	case FieldKindFloat64:
+		return f.Float64Value()
+	case FieldKindBool:
+		return f.BoolValue()
+	case FieldKindDuration:
+		return f.DurationValue()
+	case FieldKindTime:
+		return f.TimeValue()
+	default:
+		return f.val
+	}
+}
@@ -0,0 +1,119 @@
+package hc
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFieldConstructorsRoundTrip(t *testing.T) {
+	now := time.Date(2026, 3, 4, 5, 6, 7, 8, time.FixedZone("X", 3600))
+	tests := []struct {
+		field Field
+		kind  FieldKind
+		want  any
+	}{
+		{String("s", "v"), FieldKindString, "v"},
+		{Int("i", -3), FieldKindInt, -3},
+		{Int64("i64", 1<<40), FieldKindInt64, int64(1 << 40)},
+		{Float64("f", 2.5), FieldKindFloat64, 2.5},
+		{Bool("b", true), FieldKindBool, true},
+		{Bool("nb", false), FieldKindBool, false},
+		{Duration("d", 3*time.Second), FieldKindDuration, 3 * time.Second},
+		{Any("a", "x"), FieldKindAny, "x"},
+	}
+	for _, tt := range tests {
+		if tt.field.Kind() != tt.kind {
+			t.Fatalf("%s: expected kind %d, got %d", tt.field.Key, tt.kind, tt.field.Kind())
+		}
+		if got := tt.field.Value(); got != tt.want {
+			t.Fatalf("%s: expected %#v, got %#v", tt.field.Key, tt.want, got)
+		}
+	}
+
+	f := Time("t", now)
+	if f.Kind() != FieldKindTime {
+		t.Fatalf("expected time kind, got %d", f.Kind())
+	}
+	got := f.TimeValue()
+	if !got.Equal(now) || got.Location() != now.Location() {
+		t.Fatalf("expected %v, got %v", now, got)
+	}
+}
+
+func TestTimeFieldOutOfRangeFallsBackToAny(t *testing.T) {
+	far := time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := Time("t", far)
+	if f.Kind() != FieldKindAny {
+		t.Fatalf("expected any kind, got %d", f.Kind())
+	}
+	if got, ok := f.Value().(time.Time); !ok || !got.Equal(far) {
+		t.Fatalf("expected original time, got %v", f.Value())
+	}
+}
+
+func TestAddFieldsLastWriteWins(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	if !AddFields(ctx, String("user_id", "u_1"), Int("attempts", 1)) {
+		t.Fatal("expected AddFields to succeed")
+	}
+	Add(ctx, "user_id", "u_2")
+	AddFields(ctx, Int("attempts", 2), Bool("retry", true))
+	Add(ctx, "plan", "pro")
+	AddFields(ctx, String("plan", "free"))
+
+	fields := EventFields(e)
+	if fields["user_id"] != "u_2" || fields["attempts"] != 2 || fields["retry"] != true || fields["plan"] != "free" {
+		t.Fatalf("unexpected fields: %#v", fields)
+	}
+	if v, ok := e.lookup("attempts"); !ok || v != 2 {
+		t.Fatalf("expected attempts lookup, got %v", v)
+	}
+
+	list := EventFieldList(e)
+	if len(list) != len(fields) {
+		t.Fatalf("expected %d fields, got %d", len(fields), len(list))
+	}
+	for _, f := range list {
+		if f.Value() != fields[f.Key] {
+			t.Fatalf("field %s: expected %#v, got %#v", f.Key, fields[f.Key], f.Value())
+		}
+		if f.Key == "attempts" && f.Kind() != FieldKindInt {
+			t.Fatalf("expected attempts to stay typed, got kind %d", f.Kind())
+		}
+	}
+}
+
+func TestAddFieldsNoopWithoutEvent(t *testing.T) {
+	if AddFields(context.Background(), String("k", "v")) {
+		t.Fatal("expected AddFields without event to report false")
+	}
+	if EventFieldList(nil) != nil {
+		t.Fatal("expected nil field list for nil event")
+	}
+}
+
+func TestCommitUsesFieldSink(t *testing.T) {
+	ctx, _ := NewContext(context.Background())
+	AddFields(ctx, Int64("db.query_count", 3))
+	Add(ctx, "user_id", "u_1")
+
+	sink := &recordingFieldSink{}
+	if !Commit(ctx, sink, LevelInfo) {
+		t.Fatal("expected commit to succeed")
+	}
+	if sink.writes != 0 || len(sink.fields) != 2 {
+		t.Fatalf("expected one WriteFields call with 2 fields, got writes=%d fields=%v", sink.writes, sink.fields)
+	}
+}
+
+type recordingFieldSink struct {
+	writes int
+	fields []Field
+}
+
+func (s *recordingFieldSink) Write(Level, string, map[string]any) { s.writes++ }
+
+func (s *recordingFieldSink) WriteFields(_ Level, _ string, fields []Field) {
+	s.fields = append(s.fields, fields...)
+}
@@ -1,6 +1,16 @@
 package hc
 
-import "context"
+import (
+	"context"
+	"sync"
+)
+
+var fieldListPool = sync.Pool{
+	New: func() any {
+		buf := make([]Field, 0, 32)
+		return &buf
+	},
+}
 
 // Commit writes the current event snapshot immediately via sink.
 func Commit(ctx context.Context, sink Sink, level Level) bool {
@@ -14,6 +24,15 @@ func Commit(ctx context.Context, sink Sink, level Level) bool {
 	if !isValidLevel(level) {
 		return false
 	}
+	if fs, ok := sink.(FieldSink); ok {
+		bufPtr := fieldListPool.Get().(*[]Field)
+		fields := e.appendFieldList((*bufPtr)[:0])
+		fs.WriteFields(level, defaultMessage, fields)
+		clear(fields)
+		*bufPtr = fields[:0]
+		fieldListPool.Put(bufPtr)
+		return true
+	}
 	sink.Write(level, defaultMessage, EventFields(e))
 	return true
 }
@@ -0,0 +1,39 @@
+package chihappycontext
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/happytoolin/happycontext"
+	stdhappycontext "github.com/happytoolin/happycontext/integration/std"
+)
+
+// Middleware returns chi-compatible middleware that captures one event per
+// request and records the chi route pattern as http.route.
+//
+// It works both with Router.Use and wrapped around a chi.Router. Patterns of
+// mounted sub-routers are joined, e.g. "/api/orders/{id}".
+func Middleware(cfg hc.Config) func(http.Handler) http.Handler {
+	mw := stdhappycontext.MiddlewareWithRoute(cfg, routePattern)
+	return func(next http.Handler) http.Handler {
+		h := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Outside a router there is no routing context yet. Provide one
+			// so chi records matched patterns where the middleware can read
+			// them after the handler returns.
+			if chi.RouteContext(r.Context()) == nil {
+				rctx := chi.NewRouteContext()
+				if routes, ok := next.(chi.Routes); ok {
+					rctx.Routes = routes
+				}
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+func routePattern(r *http.Request) string {
+	return chi.RouteContext(r.Context()).RoutePattern()
+}
@@ -0,0 +1,72 @@
+package chihappycontext
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/happytoolin/happycontext"
+)
+
+func TestMiddlewareRecordsRouteWhenUsedInRouter(t *testing.T) {
+	sink := hc.NewTestSink()
+	r := chi.NewRouter()
+	r.Use(Middleware(hc.Config{Sink: sink, SamplingRate: 1}))
+	r.Get("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		hc.Add(r.Context(), "order_id", chi.URLParam(r, "id"))
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/42", nil))
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if events[0].Fields["http.route"] != "/orders/{id}" {
+		t.Fatalf("expected route template, got %v", events[0].Fields["http.route"])
+	}
+	if events[0].Fields["order_id"] != "42" || events[0].Fields["http.status"] != http.StatusAccepted {
+		t.Fatalf("unexpected fields: %v", events[0].Fields)
+	}
+}
+
+func TestMiddlewareRecordsMountedRouteWhenWrappingRouter(t *testing.T) {
+	sink := hc.NewTestSink()
+	orders := chi.NewRouter()
+	orders.Get("/{id}/items/{item}", func(http.ResponseWriter, *http.Request) {})
+	api := chi.NewRouter()
+	api.Mount("/api/orders", orders)
+	h := Middleware(hc.Config{Sink: sink, SamplingRate: 1})(api)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/orders/7/items/3", nil))
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if events[0].Fields["http.route"] != "/api/orders/{id}/items/{item}" {
+		t.Fatalf("expected joined route template, got %v", events[0].Fields["http.route"])
+	}
+}
+
+func TestMiddlewareNotFoundHasNoRoute(t *testing.T) {
+	sink := hc.NewTestSink()
+	r := chi.NewRouter()
+	r.Get("/orders/{id}", func(http.ResponseWriter, *http.Request) {})
+	h := Middleware(hc.Config{Sink: sink, SamplingRate: 1})(r)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if _, ok := events[0].Fields["http.route"]; ok {
+		t.Fatalf("expected no route for unmatched request, got %v", events[0].Fields["http.route"])
+	}
+	if events[0].Fields["http.status"] != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %v", events[0].Fields["http.status"])
+	}
+}
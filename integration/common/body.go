@@ -0,0 +1,142 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/happytoolin/happycontext"
+)
+
+// BodyCounter wraps a request body and counts bytes read from it.
+type BodyCounter struct {
+	io.ReadCloser
+	n       atomic.Int64
+	capture *BodyBuffer
+}
+
+// CountBody wraps body in a BodyCounter that also copies read bytes into
+// capture, which may be nil.
+// It returns nil for nil and http.NoBody bodies, which need no wrapping.
+func CountBody(body io.ReadCloser, capture *BodyBuffer) *BodyCounter {
+	if body == nil || body == http.NoBody {
+		return nil
+	}
+	return &BodyCounter{ReadCloser: body, capture: capture}
+}
+
+// Read implements io.Reader.
+func (b *BodyCounter) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n.Add(int64(n))
+	_, _ = b.capture.Write(p[:n])
+	return n, err
+}
+
+// Size returns how many bytes have been read. It is safe on a nil BodyCounter.
+func (b *BodyCounter) Size() int64 {
+	if b == nil {
+		return 0
+	}
+	return b.n.Load()
+}
+
+// BodyBuffer keeps the first bytes written to it, up to a limit.
+// All methods are safe on a nil BodyBuffer, which discards writes.
+type BodyBuffer struct {
+	mu        sync.Mutex
+	buf       []byte
+	limit     int
+	truncated bool
+}
+
+// NewBodyBuffer returns a buffer for cfg, or nil when body capture is disabled.
+//
+// cfg must be normalized with NormalizeConfig.
+func NewBodyBuffer(cfg hc.Config) *BodyBuffer {
+	if !cfg.BodyCapture.Enabled {
+		return nil
+	}
+	return &BodyBuffer{limit: cfg.BodyCapture.MaxBytes}
+}
+
+// Write implements io.Writer. It never fails; bytes past the limit are
+// dropped and mark the buffer truncated.
+func (b *BodyBuffer) Write(p []byte) (int, error) {
+	if b == nil || len(p) == 0 {
+		return len(p), nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	room := b.limit - len(b.buf)
+	if room < len(p) {
+		b.truncated = true
+	}
+	if room > 0 {
+		b.buf = append(b.buf, p[:min(room, len(p))]...)
+	}
+	return len(p), nil
+}
+
+// Full reports whether further writes would be dropped.
+func (b *BodyBuffer) Full() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.buf) >= b.limit
+}
+
+func (b *BodyBuffer) contents() ([]byte, bool) {
+	if b == nil {
+		return nil, false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf, b.truncated
+}
+
+// attachBody records a captured body under field when its content type is
+// allowlisted. Complete JSON bodies are recorded as parsed values.
+func attachBody(ctx context.Context, cfg hc.BodyCaptureConfig, field string, body *BodyBuffer, contentType string) {
+	data, truncated := body.contents()
+	if len(data) == 0 {
+		return
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !matchesMediaType(cfg.ContentTypes, mediaType) {
+		return
+	}
+
+	var value any = string(data)
+	if !truncated && isJSONMediaType(mediaType) {
+		var parsed any
+		if json.Unmarshal(data, &parsed) == nil {
+			value = parsed
+		}
+	}
+	hc.Add(ctx, field, value)
+	if truncated {
+		hc.Add(ctx, field+"_truncated", true)
+	}
+}
+
+func matchesMediaType(patterns []string, mediaType string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, mediaType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
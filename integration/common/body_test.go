@@ -0,0 +1,157 @@
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	hc "github.com/happytoolin/happycontext"
+)
+
+func TestCountBody(t *testing.T) {
+	if CountBody(nil, nil) != nil || CountBody(http.NoBody, nil) != nil {
+		t.Fatal("expected nil and NoBody to be left unwrapped")
+	}
+	var missing *BodyCounter
+	if missing.Size() != 0 {
+		t.Fatal("expected nil counter size 0")
+	}
+
+	capture := &BodyBuffer{limit: 8}
+	body := CountBody(io.NopCloser(strings.NewReader("hello world")), capture)
+	buf := make([]byte, 5)
+	if _, err := body.Read(buf); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if body.Size() != 5 {
+		t.Fatalf("size after partial read = %d, want 5", body.Size())
+	}
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if body.Size() != 11 {
+		t.Fatalf("size = %d, want 11", body.Size())
+	}
+	if got, truncated := capture.contents(); string(got) != "hello wo" || !truncated {
+		t.Fatalf("capture = %q truncated=%v", got, truncated)
+	}
+}
+
+func TestBodyBufferDisabled(t *testing.T) {
+	b := NewBodyBuffer(hc.Config{})
+	if b != nil {
+		t.Fatal("expected nil buffer when capture is disabled")
+	}
+	if n, err := b.Write([]byte("x")); n != 1 || err != nil {
+		t.Fatalf("nil write = %d, %v", n, err)
+	}
+	if !b.Full() {
+		t.Fatal("expected nil buffer to report full")
+	}
+}
+
+func TestFinalizeRequestAttachesBodiesOnError(t *testing.T) {
+	sink := hc.NewTestSink()
+	cfg := NormalizeConfig(hc.Config{
+		Sink:         sink,
+		SamplingRate: 1,
+		BodyCapture:  hc.BodyCaptureConfig{Enabled: true, MaxBytes: 32},
+		Redactor: hc.NewRedactor(hc.RedactorOptions{Rules: []hc.RedactRule{
+			{Key: hc.MatchKey("password")},
+		}}),
+	})
+	reqHeader := http.Header{"Content-Type": {"application/json; charset=utf-8"}}
+	respHeader := http.Header{"Content-Type": {"text/plain"}}
+
+	ctx, event := StartRequest(context.Background(), http.MethodPost, "/login")
+	FinalizeRequest(cfg, FinalizeInput{
+		Ctx:            ctx,
+		Event:          event,
+		Method:         http.MethodPost,
+		Path:           "/login",
+		StatusCode:     http.StatusInternalServerError,
+		Header:         reqHeader.Get,
+		ResponseHeader: respHeader.Get,
+		RequestBody:    bufferBody(cfg, `{"user":"a","password":"p"}`),
+
+		ReadResponseBody: func() []byte { return []byte(strings.Repeat("x", 40)) },
+	})
+
+	fields := sink.Events()[0].Fields
+	reqBody, ok := fields["http.request.body"].(map[string]any)
+	if !ok || reqBody["user"] != "a" || reqBody["password"] != "[REDACTED]" {
+		t.Fatalf("request body = %#v", fields["http.request.body"])
+	}
+	if fields["http.response.body"] != strings.Repeat("x", 32) || fields["http.response.body_truncated"] != true {
+		t.Fatalf("response body = %v truncated=%v", fields["http.response.body"], fields["http.response.body_truncated"])
+	}
+}
+
+func TestFinalizeRequestSkipsBodies(t *testing.T) {
+	cfg := NormalizeConfig(hc.Config{
+		SamplingRate: 1,
+		BodyCapture:  hc.BodyCaptureConfig{Enabled: true},
+	})
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		keep        bool
+	}{
+		{name: "healthy request", status: http.StatusOK, contentType: "application/json"},
+		{name: "disallowed content type", status: http.StatusInternalServerError, contentType: "application/octet-stream"},
+		{name: "kept but disallowed", status: http.StatusOK, contentType: "image/png", keep: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := hc.NewTestSink()
+			cfg.Sink = sink
+			header := http.Header{"Content-Type": {tt.contentType}}
+			ctx, event := StartRequest(context.Background(), http.MethodPost, "/upload")
+			if tt.keep {
+				hc.KeepEvent(ctx, "debug")
+			}
+			read := false
+			FinalizeRequest(cfg, FinalizeInput{
+				Ctx:        ctx,
+				Event:      event,
+				StatusCode: tt.status,
+				Header:     header.Get,
+				ReadRequestBody: func() []byte {
+					read = true
+					return []byte(`{"a":1}`)
+				},
+			})
+			if _, ok := sink.Events()[0].Fields["http.request.body"]; ok {
+				t.Fatal("expected body not to be attached")
+			}
+			if tt.status < http.StatusInternalServerError && !tt.keep && read {
+				t.Fatal("expected body not to be read for a healthy request")
+			}
+		})
+	}
+
+	sink := hc.NewTestSink()
+	cfg.Sink = sink
+	header := http.Header{"Content-Type": {"application/json"}}
+	ctx, event := StartRequest(context.Background(), http.MethodPost, "/upload")
+	hc.KeepEvent(ctx, "debug")
+	FinalizeRequest(cfg, FinalizeInput{
+		Ctx:         ctx,
+		Event:       event,
+		StatusCode:  http.StatusOK,
+		Header:      header.Get,
+		RequestBody: bufferBody(cfg, `{"a":1}`),
+	})
+	if body, ok := sink.Events()[0].Fields["http.request.body"].(map[string]any); !ok || body["a"] != float64(1) {
+		t.Fatalf("kept request body = %#v", sink.Events()[0].Fields["http.request.body"])
+	}
+}
+
+func bufferBody(cfg hc.Config, body string) *BodyBuffer {
+	b := NewBodyBuffer(cfg)
+	_, _ = b.Write([]byte(body))
+	return b
+}
@@ -0,0 +1,118 @@
+package common
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// ClientIPInput contains connection data used to resolve the client address.
+type ClientIPInput struct {
+	// RemoteAddr is the immediate peer, as host:port or a bare IP.
+	RemoteAddr string
+	// HeaderValues returns every value of a request header, across repeated lines.
+	HeaderValues func(string) []string
+	// TrustedProxies lists peers whose forwarding headers are honored.
+	TrustedProxies []netip.Prefix
+}
+
+// ResolveClientIP returns the client address for a request.
+//
+// Forwarding headers are honored only when the immediate peer is a trusted
+// proxy. Forwarded takes precedence over X-Forwarded-For, which takes
+// precedence over X-Real-IP. Forwarded and X-Forwarded-For chains are walked
+// right to left, skipping trusted proxies, and the first untrusted address is
+// the client. When every hop is trusted the leftmost is used, and an
+// unparsable hop stops the walk at the nearest address already verified.
+// It returns "" when RemoteAddr cannot be parsed.
+func ResolveClientIP(in ClientIPInput) string {
+	peer, ok := parseAddr(in.RemoteAddr)
+	if !ok {
+		return ""
+	}
+	if !isTrusted(peer, in.TrustedProxies) || in.HeaderValues == nil {
+		return peer.String()
+	}
+
+	if chain := forwardedFor(in.HeaderValues("Forwarded")); len(chain) > 0 {
+		return walkChain(peer, chain, in.TrustedProxies).String()
+	}
+	if chain := splitList(in.HeaderValues("X-Forwarded-For")); len(chain) > 0 {
+		return walkChain(peer, chain, in.TrustedProxies).String()
+	}
+	for _, v := range in.HeaderValues("X-Real-IP") {
+		if addr, ok := parseAddr(strings.TrimSpace(v)); ok {
+			return addr.String()
+		}
+	}
+	return peer.String()
+}
+
+func walkChain(peer netip.Addr, chain []string, trusted []netip.Prefix) netip.Addr {
+	client := peer
+	for i := len(chain) - 1; i >= 0; i-- {
+		addr, ok := parseAddr(chain[i])
+		if !ok {
+			return client
+		}
+		client = addr
+		if !isTrusted(addr, trusted) {
+			return addr
+		}
+	}
+	return client
+}
+
+// forwardedFor extracts for= values from RFC 7239 Forwarded header values.
+func forwardedFor(values []string) []string {
+	var out []string
+	for _, element := range splitList(values) {
+		for _, pair := range strings.Split(element, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok || !strings.EqualFold(name, "for") {
+				continue
+			}
+			out = append(out, strings.Trim(value, `"`))
+		}
+	}
+	return out
+}
+
+func splitList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+// parseAddr parses a bare IP, host:port, or bracketed IPv6 with optional port.
+func parseAddr(s string) (netip.Addr, bool) {
+	if s == "" {
+		return netip.Addr{}, false
+	}
+	if addr, err := netip.ParseAddr(s); err == nil {
+		return addr.Unmap(), true
+	}
+	if addrPort, err := netip.ParseAddrPort(s); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		if addr, err := netip.ParseAddr(s[1 : len(s)-1]); err == nil {
+			return addr.Unmap(), true
+		}
+	}
+	return netip.Addr{}, false
+}
+
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
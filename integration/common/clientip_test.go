@@ -0,0 +1,96 @@
+package common
+
+import (
+	"net/http"
+	"net/netip"
+	"testing"
+)
+
+func TestResolveClientIP(t *testing.T) {
+	trusted := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	}
+
+	tests := []struct {
+		name    string
+		remote  string
+		headers http.Header
+		trusted []netip.Prefix
+		want    string
+	}{
+		{name: "no proxies", remote: "203.0.113.9:4000", want: "203.0.113.9"},
+		{
+			name:    "untrusted peer ignores headers",
+			remote:  "198.51.100.1:4000",
+			headers: http.Header{"X-Forwarded-For": {"1.1.1.1"}},
+			trusted: trusted,
+			want:    "198.51.100.1",
+		},
+		{
+			name:    "xff skips trusted hops right to left",
+			remote:  "10.0.0.1:4000",
+			headers: http.Header{"X-Forwarded-For": {"6.6.6.6, 203.0.113.7, 10.0.0.2"}},
+			trusted: trusted,
+			want:    "203.0.113.7",
+		},
+		{
+			name:    "xff across repeated lines",
+			remote:  "10.0.0.1:4000",
+			headers: http.Header{"X-Forwarded-For": {"6.6.6.6", "203.0.113.7"}},
+			trusted: trusted,
+			want:    "203.0.113.7",
+		},
+		{
+			name:    "all hops trusted uses leftmost",
+			remote:  "10.0.0.1:4000",
+			headers: http.Header{"X-Forwarded-For": {"10.1.1.1, 10.0.0.2"}},
+			trusted: trusted,
+			want:    "10.1.1.1",
+		},
+		{
+			name:    "invalid hop stops walk",
+			remote:  "10.0.0.1:4000",
+			headers: http.Header{"X-Forwarded-For": {"203.0.113.7, garbage, 10.0.0.2"}},
+			trusted: trusted,
+			want:    "10.0.0.2",
+		},
+		{
+			name:   "forwarded wins over xff",
+			remote: "10.0.0.1:4000",
+			headers: http.Header{
+				"Forwarded":       {`for=192.0.2.60;proto=http, for="[2001:db8:cafe::17]:4711"`},
+				"X-Forwarded-For": {"198.51.100.3"},
+			},
+			trusted: trusted,
+			want:    "192.0.2.60",
+		},
+		{
+			name:    "x-real-ip fallback",
+			remote:  "10.0.0.1:4000",
+			headers: http.Header{"X-Real-Ip": {"203.0.113.8"}},
+			trusted: trusted,
+			want:    "203.0.113.8",
+		},
+		{name: "ipv6 peer", remote: "[2001:db9::1]:443", want: "2001:db9::1"},
+		{name: "mapped ipv4 peer", remote: "[::ffff:203.0.113.9]:80", want: "203.0.113.9"},
+		{name: "unparsable peer", remote: "pipe", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := tt.headers
+			if headers == nil {
+				headers = http.Header{}
+			}
+			got := ResolveClientIP(ClientIPInput{
+				RemoteAddr:     tt.remote,
+				HeaderValues:   headers.Values,
+				TrustedProxies: tt.trusted,
+			})
+			if got != tt.want {
+				t.Fatalf("client IP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
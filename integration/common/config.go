@@ -1,6 +1,11 @@
 package common
 
-import "github.com/happytoolin/happycontext"
+import (
+	"slices"
+	"strings"
+
+	"github.com/happytoolin/happycontext"
+)
 
 // DefaultMessage is used when Config.Message is empty.
 const DefaultMessage = "request_completed"
@@ -32,6 +37,93 @@ func NormalizeConfig(cfg hc.Config) hc.Config {
 	if cfg.Message == "" {
 		cfg.Message = DefaultMessage
 	}
+	if len(cfg.ErrorLevels) > 0 {
+		levels := make(map[hc.ErrorClass]hc.Level, len(cfg.ErrorLevels))
+		for class, level := range cfg.ErrorLevels {
+			if isValidLevel(level) {
+				levels[class] = level
+			}
+		}
+		cfg.ErrorLevels = levels
+	}
+	if len(cfg.Headers.Request) > 0 || len(cfg.Headers.Response) > 0 {
+		cfg.Headers = normalizeHeaders(cfg.Headers)
+	}
+	if cfg.BodyCapture.Enabled {
+		cfg.BodyCapture = normalizeBodyCapture(cfg.BodyCapture)
+	}
+	if cfg.RequestID.Enabled {
+		cfg.RequestID = normalizeRequestID(cfg.RequestID)
+	}
+	if cfg.PanicStack.Enabled && cfg.PanicStack.MaxFrames <= 0 {
+		cfg.PanicStack.MaxFrames = defaultPanicStackFrames
+	}
+	return cfg
+}
+
+var defaultSensitiveHeaders = []string{"authorization", "cookie", "set-cookie", "proxy-authorization"}
+
+func normalizeHeaders(cfg hc.HeaderConfig) hc.HeaderConfig {
+	cfg.Request = lowerNames(cfg.Request)
+	cfg.Response = lowerNames(cfg.Response)
+	sensitive := cfg.Sensitive
+	if !cfg.SkipDefaultSensitive {
+		sensitive = append(slices.Clip(defaultSensitiveHeaders), sensitive...)
+	}
+	cfg.Sensitive = lowerNames(sensitive)
+	return cfg
+}
+
+// lowerNames lowercases and trims names, dropping empty and duplicate entries.
+func lowerNames(names []string) []string {
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" && !slices.Contains(out, name) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+const defaultBodyCaptureBytes = 4096
+
+var defaultBodyContentTypes = []string{
+	"application/json",
+	"application/*+json",
+	"text/plain",
+	"application/x-www-form-urlencoded",
+}
+
+func normalizeBodyCapture(cfg hc.BodyCaptureConfig) hc.BodyCaptureConfig {
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = defaultBodyCaptureBytes
+	}
+	if len(cfg.ContentTypes) == 0 {
+		cfg.ContentTypes = defaultBodyContentTypes
+	} else {
+		cfg.ContentTypes = lowerNames(cfg.ContentTypes)
+	}
+	return cfg
+}
+
+func normalizeRequestID(cfg hc.RequestIDConfig) hc.RequestIDConfig {
+	headers := make([]string, 0, len(cfg.Headers))
+	for _, h := range cfg.Headers {
+		if h != "" {
+			headers = append(headers, h)
+		}
+	}
+	if len(headers) == 0 {
+		headers = append(headers, hc.DefaultRequestIDHeader)
+	}
+	cfg.Headers = headers
+	if cfg.ResponseHeader == "" {
+		cfg.ResponseHeader = headers[0]
+	}
+	if cfg.Generator == nil {
+		cfg.Generator = hc.NewRequestID
+	}
 	return cfg
 }
 
@@ -46,6 +138,30 @@ func MergeLevelWithFloor(autoLevel, requestedLevel hc.Level, hasRequested bool)
 	return autoLevel
 }
 
+// errorLevel returns the level implied by the event's errors and status, and
+// whether there is an error at all. The level only sets the event level;
+// bypassesSampling decides sampling independently of cfg.ErrorLevels.
+func errorLevel(cfg hc.Config, event *hc.Event, statusCode int) (hc.Level, bool) {
+	if statusCode >= 500 {
+		return hc.LevelError, true
+	}
+	class, ok := hc.EventErrorClass(event)
+	if !ok {
+		return "", false
+	}
+	if level, ok := cfg.ErrorLevels[class]; ok {
+		return level, true
+	}
+	switch class {
+	case hc.ErrorClassClient:
+		return hc.LevelWarn, true
+	case hc.ErrorClassCanceled:
+		return hc.LevelInfo, true
+	default:
+		return hc.LevelError, true
+	}
+}
+
 func levelRank(level hc.Level) int {
 	switch level {
 	case hc.LevelDebug:
@@ -69,3 +185,13 @@ func isValidLevel(level hc.Level) bool {
 		return false
 	}
 }
+
+// bypassesSampling reports whether the event must be kept regardless of
+// sampling: a status of 500 or above, or a server or transient error.
+func bypassesSampling(event *hc.Event, statusCode int) bool {
+	if statusCode >= 500 {
+		return true
+	}
+	class, ok := hc.EventErrorClass(event)
+	return ok && (class == hc.ErrorClassServer || class == hc.ErrorClassTransient)
+}
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/happytoolin/happycontext"
@@ -77,3 +78,73 @@ func TestMergeLevelWithFloor(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeConfigRequestIDDefaults(t *testing.T) {
+	got := NormalizeConfig(hc.Config{RequestID: hc.RequestIDConfig{Enabled: true, Headers: []string{"", "X-Correlation-ID"}}})
+	if len(got.RequestID.Headers) != 1 || got.RequestID.Headers[0] != "X-Correlation-ID" {
+		t.Fatalf("headers = %v", got.RequestID.Headers)
+	}
+	if got.RequestID.ResponseHeader != "X-Correlation-ID" {
+		t.Fatalf("response header = %q", got.RequestID.ResponseHeader)
+	}
+	if got.RequestID.Generator == nil {
+		t.Fatal("expected default generator")
+	}
+
+	got = NormalizeConfig(hc.Config{RequestID: hc.RequestIDConfig{Enabled: true}})
+	if len(got.RequestID.Headers) != 1 || got.RequestID.Headers[0] != hc.DefaultRequestIDHeader {
+		t.Fatalf("headers = %v", got.RequestID.Headers)
+	}
+
+	got = NormalizeConfig(hc.Config{})
+	if got.RequestID.Headers != nil || got.RequestID.Generator != nil {
+		t.Fatal("expected disabled request ID config to be left untouched")
+	}
+}
+
+func TestNormalizeConfigHeaders(t *testing.T) {
+	got := NormalizeConfig(hc.Config{Headers: hc.HeaderConfig{Request: []string{"User-Agent", "user-agent", " ", "X-Tenant"}}})
+	if len(got.Headers.Request) != 2 || got.Headers.Request[0] != "user-agent" || got.Headers.Request[1] != "x-tenant" {
+		t.Fatalf("request headers = %v", got.Headers.Request)
+	}
+	if len(got.Headers.Sensitive) != len(defaultSensitiveHeaders) {
+		t.Fatalf("sensitive headers = %v", got.Headers.Sensitive)
+	}
+
+	got = NormalizeConfig(hc.Config{Headers: hc.HeaderConfig{Response: []string{"Set-Cookie"}, Sensitive: []string{"X-Api-Key"}}})
+	if len(got.Headers.Sensitive) != len(defaultSensitiveHeaders)+1 || !slices.Contains(got.Headers.Sensitive, "x-api-key") {
+		t.Fatalf("expected custom sensitive headers to extend the defaults, got %v", got.Headers.Sensitive)
+	}
+
+	got = NormalizeConfig(hc.Config{Headers: hc.HeaderConfig{Response: []string{"Set-Cookie"}, Sensitive: []string{}, SkipDefaultSensitive: true}})
+	if len(got.Headers.Sensitive) != 0 {
+		t.Fatalf("expected defaults to be skipped, got %v", got.Headers.Sensitive)
+	}
+}
+
+func TestNormalizeConfigBodyCapture(t *testing.T) {
+	got := NormalizeConfig(hc.Config{BodyCapture: hc.BodyCaptureConfig{Enabled: true}})
+	if got.BodyCapture.MaxBytes != defaultBodyCaptureBytes || len(got.BodyCapture.ContentTypes) != len(defaultBodyContentTypes) {
+		t.Fatalf("body capture = %+v", got.BodyCapture)
+	}
+
+	got = NormalizeConfig(hc.Config{BodyCapture: hc.BodyCaptureConfig{
+		Enabled:      true,
+		MaxBytes:     10,
+		ContentTypes: []string{"Text/*", "text/*"},
+	}})
+	if got.BodyCapture.MaxBytes != 10 || len(got.BodyCapture.ContentTypes) != 1 || got.BodyCapture.ContentTypes[0] != "text/*" {
+		t.Fatalf("body capture = %+v", got.BodyCapture)
+	}
+}
+
+func TestNormalizeConfigPanicStack(t *testing.T) {
+	got := NormalizeConfig(hc.Config{PanicStack: hc.PanicStackConfig{Enabled: true}})
+	if got.PanicStack.MaxFrames != defaultPanicStackFrames {
+		t.Fatalf("panic stack = %+v", got.PanicStack)
+	}
+	got = NormalizeConfig(hc.Config{PanicStack: hc.PanicStackConfig{Enabled: true, MaxFrames: 5}})
+	if got.PanicStack.MaxFrames != 5 {
+		t.Fatalf("panic stack = %+v", got.PanicStack)
+	}
+}
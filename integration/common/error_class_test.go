@@ -0,0 +1,102 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/happytoolin/happycontext"
+)
+
+func finalizeWithError(t *testing.T, cfg hc.Config, status int, record func(context.Context)) []hc.CapturedEvent {
+	t.Helper()
+	ctx, event := StartRequest(context.Background(), "POST", "/signup")
+	record(ctx)
+	sink := hc.NewTestSink()
+	cfg.Sink = sink
+	FinalizeRequest(NormalizeConfig(cfg), FinalizeInput{
+		Ctx:        ctx,
+		Event:      event,
+		Method:     "POST",
+		Path:       "/signup",
+		StatusCode: status,
+	})
+	return sink.Events()
+}
+
+func TestFinalizeRequestClientErrorLogsWarnAndIsSampled(t *testing.T) {
+	clientErr := func(ctx context.Context) { hc.Error(ctx, errors.New("invalid email"), hc.ErrorClassClient) }
+
+	events := finalizeWithError(t, hc.Config{SamplingRate: 1}, 400, clientErr)
+	if len(events) != 1 || events[0].Level != hc.LevelWarn {
+		t.Fatalf("expected one WARN event, got %+v", events)
+	}
+
+	if events := finalizeWithError(t, hc.Config{SamplingRate: 0}, 400, clientErr); len(events) != 0 {
+		t.Fatal("expected client error to be sampled out")
+	}
+}
+
+func TestFinalizeRequestServerErrorsBypassSampling(t *testing.T) {
+	events := finalizeWithError(t, hc.Config{SamplingRate: 0}, 200, func(ctx context.Context) {
+		hc.Error(ctx, errors.New("invalid email"), hc.ErrorClassClient)
+		hc.Error(ctx, errors.New("db down"))
+	})
+	if len(events) != 1 || events[0].Level != hc.LevelError {
+		t.Fatalf("expected one ERROR event, got %+v", events)
+	}
+
+	events = finalizeWithError(t, hc.Config{SamplingRate: 0}, 503, func(ctx context.Context) {
+		hc.Error(ctx, context.Canceled)
+	})
+	if len(events) != 1 || events[0].Level != hc.LevelError {
+		t.Fatalf("expected 5xx to log at ERROR, got %+v", events)
+	}
+}
+
+func TestFinalizeRequestErrorLevelsOverride(t *testing.T) {
+	cfg := hc.Config{
+		SamplingRate: 1,
+		ErrorLevels: map[hc.ErrorClass]hc.Level{
+			hc.ErrorClassClient:   hc.LevelError,
+			hc.ErrorClassCanceled: "bogus",
+		},
+	}
+	events := finalizeWithError(t, cfg, 422, func(ctx context.Context) {
+		hc.Error(ctx, errors.New("invalid email"), hc.ErrorClassClient)
+	})
+	if len(events) != 1 || events[0].Level != hc.LevelError {
+		t.Fatalf("expected client error promoted to ERROR, got %+v", events)
+	}
+
+	events = finalizeWithError(t, cfg, 200, func(ctx context.Context) {
+		hc.Error(ctx, context.Canceled)
+	})
+	if len(events) != 1 || events[0].Level != hc.LevelInfo {
+		t.Fatalf("expected invalid override ignored and canceled at INFO, got %+v", events)
+	}
+}
+
+func TestFinalizeRequestErrorLevelsDoNotChangeSamplingBypass(t *testing.T) {
+	serverErr := func(ctx context.Context) { hc.Error(ctx, errors.New("db down")) }
+	clientErr := func(ctx context.Context) { hc.Error(ctx, errors.New("invalid email"), hc.ErrorClassClient) }
+	cfg := hc.Config{
+		SamplingRate: 0,
+		ErrorLevels: map[hc.ErrorClass]hc.Level{
+			hc.ErrorClassServer: hc.LevelWarn,
+			hc.ErrorClassClient: hc.LevelError,
+		},
+	}
+
+	events := finalizeWithError(t, cfg, 200, serverErr)
+	if len(events) != 1 || events[0].Level != hc.LevelWarn {
+		t.Fatalf("expected server error kept at WARN, got %+v", events)
+	}
+	if rate := events[0].Fields["sample_rate"]; rate != 1.0 {
+		t.Fatalf("sample_rate = %v, want 1", rate)
+	}
+
+	if events := finalizeWithError(t, cfg, 400, clientErr); len(events) != 0 {
+		t.Fatalf("expected client error promoted to ERROR to stay sampled, got %+v", events)
+	}
+}
@@ -0,0 +1,34 @@
+package common
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	hc "github.com/happytoolin/happycontext"
+)
+
+// Excluded reports whether a request path bypasses event capture.
+//
+// Routes are not checked here: FinalizeRequest discards excluded routes once
+// it knows whether the request failed.
+func Excluded(cfg hc.ExcludeConfig, reqPath string) bool {
+	if slices.Contains(cfg.Paths, reqPath) {
+		return true
+	}
+	for _, prefix := range cfg.PathPrefixes {
+		if strings.HasPrefix(reqPath, prefix) {
+			return true
+		}
+	}
+	for _, pattern := range cfg.PathGlobs {
+		if ok, _ := path.Match(pattern, reqPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func excludedRoute(cfg hc.ExcludeConfig, route string) bool {
+	return route != "" && slices.Contains(cfg.Routes, route)
+}
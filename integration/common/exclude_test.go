@@ -0,0 +1,89 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	hc "github.com/happytoolin/happycontext"
+)
+
+func TestExcluded(t *testing.T) {
+	cfg := hc.ExcludeConfig{
+		Paths:        []string{"/healthz"},
+		PathPrefixes: []string{"/debug/pprof/"},
+		PathGlobs:    []string{"/static/*/*.js", "["},
+		Routes:       []string{"/metrics/:kind"},
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{name: "exact path", path: "/healthz", want: true},
+		{name: "exact path is not a prefix", path: "/healthz/live", want: false},
+		{name: "prefix", path: "/debug/pprof/heap", want: true},
+		{name: "glob", path: "/static/v1/app.js", want: true},
+		{name: "glob does not cross segments", path: "/static/v1/x/app.js", want: false},
+		{name: "routes are checked at finalization", path: "/metrics/cpu", want: false},
+		{name: "unrelated", path: "/orders/1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Excluded(cfg, tt.path); got != tt.want {
+				t.Fatalf("Excluded(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFinalizeRequestDiscardsExcludedRoute(t *testing.T) {
+	sink := hc.NewTestSink()
+	cfg := NormalizeConfig(hc.Config{
+		Sink:         sink,
+		SamplingRate: 1,
+		Exclude:      hc.ExcludeConfig{Routes: []string{"GET /healthz"}},
+	})
+
+	ctx, event := StartRequest(context.Background(), http.MethodGet, "/healthz")
+	finalized := false
+	hc.OnFinalize(ctx, func(hc.Level, map[string]any) { finalized = true })
+	FinalizeRequest(cfg, FinalizeInput{Ctx: ctx, Event: event, Route: "GET /healthz", StatusCode: http.StatusOK})
+
+	if len(sink.Events()) != 0 || finalized {
+		t.Fatalf("expected excluded route to be discarded, events=%d finalized=%v", len(sink.Events()), finalized)
+	}
+}
+
+func TestFinalizeRequestKeepsFailedExcludedRoute(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		recovered any
+	}{
+		{name: "panic", status: http.StatusInternalServerError, recovered: "boom"},
+		{name: "5xx", status: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := hc.NewTestSink()
+			cfg := NormalizeConfig(hc.Config{
+				Sink:         sink,
+				SamplingRate: 0,
+				Exclude:      hc.ExcludeConfig{Routes: []string{"GET /healthz"}},
+			})
+
+			ctx, event := StartRequest(context.Background(), http.MethodGet, "/healthz")
+			FinalizeRequest(cfg, FinalizeInput{Ctx: ctx, Event: event, Route: "GET /healthz", StatusCode: tt.status, Recovered: tt.recovered})
+
+			events := sink.Events()
+			if len(events) != 1 || events[0].Level != hc.LevelError {
+				t.Fatalf("expected failed excluded route to log at ERROR, got %+v", events)
+			}
+			if _, ok := events[0].Fields["panic"]; ok != (tt.recovered != nil) {
+				t.Fatalf("panic field present = %v, want %v", ok, tt.recovered != nil)
+			}
+		})
+	}
+}
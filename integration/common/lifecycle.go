@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	hc "github.com/happytoolin/happycontext"
@@ -19,42 +22,222 @@ type FinalizeInput struct {
 	StatusCode int
 	Err        error
 	Recovered  any
+	// Header optionally exposes request headers to samplers.
+	Header func(string) string
+	// ResponseHeader returns a response header value, or "" when absent.
+	ResponseHeader func(string) string
+	// RequestBodySize is the number of request body bytes read.
+	RequestBodySize int64
+	// ResponseBodySize is the number of response body bytes written.
+	ResponseBodySize int64
+	// RequestBody holds the captured request body prefix, if any.
+	RequestBody *BodyBuffer
+	// ResponseBody holds the captured response body prefix, if any.
+	ResponseBody *BodyBuffer
+	// ReadRequestBody returns the whole request body for frameworks that
+	// buffer it. It is used when RequestBody is nil, and only called once
+	// the event is known to attach bodies.
+	ReadRequestBody func() []byte
+	// ReadResponseBody is the ReadRequestBody counterpart for ResponseBody.
+	ReadResponseBody func() []byte
+}
+
+// StartInput contains request data required to start an event.
+type StartInput struct {
+	Ctx    context.Context
+	Method string
+	Path   string
+	// Header returns a request header value, or "" when absent.
+	Header func(string) string
+	// SetHeader sets a response header. It is called before the handler runs.
+	SetHeader func(name, value string)
+	// RemoteAddr is the connection peer address, as host:port or a bare IP.
+	RemoteAddr string
+	// HeaderValues returns every value of a request header. It is used to
+	// read forwarding headers that may span repeated header lines.
+	HeaderValues func(string) []string
 }
 
 // StartRequest initializes request context and base HTTP fields.
 func StartRequest(baseCtx context.Context, method, path string) (context.Context, *hc.Event) {
+	return startRequest(baseCtx, hc.Config{}, method, path)
+}
+
+func startRequest(baseCtx context.Context, cfg hc.Config, method, path string) (context.Context, *hc.Event) {
 	if baseCtx == nil {
 		baseCtx = context.Background()
 	}
-	ctx, event := hc.NewContext(baseCtx)
-	hc.Add(ctx, "http.method", method, "http.path", path)
+	ctx, event := hc.NewContextWithConfig(baseCtx, cfg)
+	hc.AddFields(ctx, hc.String("http.method", method), hc.String("http.path", path))
 	return ctx, event
 }
 
+// StartRequestWithConfig initializes request context and base HTTP fields,
+// then applies config-driven start behavior such as request IDs.
+//
+// cfg must be normalized with NormalizeConfig.
+func StartRequestWithConfig(cfg hc.Config, in StartInput) (context.Context, *hc.Event) {
+	ctx, event := startRequest(in.Ctx, cfg, in.Method, in.Path)
+	if ip := ResolveClientIP(ClientIPInput{
+		RemoteAddr:     in.RemoteAddr,
+		HeaderValues:   in.HeaderValues,
+		TrustedProxies: cfg.TrustedProxies,
+	}); ip != "" {
+		hc.AddFields(ctx, hc.String("client.address", ip))
+	}
+	recordHeaders(ctx, requestHeaderPrefix, cfg.Headers.Request, cfg.Headers.Sensitive, in.Header)
+	applyRequestID(ctx, cfg.RequestID, in.Header, in.SetHeader)
+	return ctx, event
+}
+
+const (
+	requestHeaderPrefix  = "http.request.header."
+	responseHeaderPrefix = "http.response.header."
+	redactedHeaderValue  = "[REDACTED]"
+)
+
+// recordHeaders adds allowlisted header values under prefix.
+// names and sensitive must be lowercase, as produced by NormalizeConfig.
+func recordHeaders(ctx context.Context, prefix string, names, sensitive []string, get func(string) string) {
+	if get == nil {
+		return
+	}
+	for _, name := range names {
+		v := get(name)
+		if v == "" {
+			continue
+		}
+		if slices.Contains(sensitive, name) {
+			v = redactedHeaderValue
+		} else {
+			// Some frameworks reuse header buffers after the request.
+			v = strings.Clone(v)
+		}
+		hc.Add(ctx, prefix+name, v)
+	}
+}
+
+func applyRequestID(ctx context.Context, cfg hc.RequestIDConfig, header func(string) string, setHeader func(string, string)) {
+	if !cfg.Enabled {
+		return
+	}
+	id := ""
+	if header != nil {
+		for _, name := range cfg.Headers {
+			if v := header(name); validRequestID(v) {
+				// Some frameworks reuse header buffers after the request.
+				id = strings.Clone(v)
+				break
+			}
+		}
+	}
+	if id == "" && cfg.Generator != nil {
+		id = cfg.Generator()
+	}
+	if !hc.SetRequestID(ctx, id) {
+		return
+	}
+	if setHeader != nil && cfg.ResponseHeader != "" {
+		setHeader(cfg.ResponseHeader, id)
+	}
+}
+
+const maxRequestIDLength = 128
+
+// validRequestID rejects incoming IDs that are empty, oversized, or contain
+// characters outside printable ASCII, so clients cannot inject log content.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
 // FinalizeRequest computes status/level/sampling and writes the final snapshot.
+// Events whose route is listed in cfg.Exclude.Routes are discarded unless the
+// request failed in a way that bypasses sampling, such as a panic or a 5xx.
 func FinalizeRequest(cfg hc.Config, in FinalizeInput) {
 	if cfg.Sink == nil || in.Event == nil || in.Ctx == nil {
 		return
 	}
 
-	annotateFailures(in.Ctx, in.Err, in.Recovered)
+	annotateFailures(in.Ctx, cfg.PanicStack, in.Err, in.Recovered, in.StatusCode)
+	if excludedRoute(cfg.Exclude, in.Route) && !bypassesSampling(in.Event, in.StatusCode) {
+		return
+	}
 	if in.Route != "" {
 		hc.SetRoute(in.Ctx, in.Route)
 	}
 
+	recordHeaders(in.Ctx, responseHeaderPrefix, cfg.Headers.Response, cfg.Headers.Sensitive, in.ResponseHeader)
+	hc.AddFields(in.Ctx,
+		hc.Int64("http.request.body_size", in.RequestBodySize),
+		hc.Int64("http.response.body_size", in.ResponseBodySize),
+	)
 	duration := annotateTiming(in.Ctx, in.Event, in.StatusCode)
-	hasError := hc.EventHasError(in.Event) || in.StatusCode >= 500
-	level := resolveLevel(in.Ctx, hasError)
-	if !shouldWriteEvent(cfg, sampleInput{
+	errLevel, hasError := errorLevel(cfg, in.Event, in.StatusCode)
+	if cfg.BodyCapture.Enabled {
+		attachBodies(cfg.BodyCapture, in, hasError)
+	}
+	level := resolveLevel(in.Ctx, hc.LevelInfo, errLevel)
+	writeEvent(cfg, level, sampleInput{
 		Method:     in.Method,
 		Path:       in.Path,
-		HasError:   hasError,
+		HasError:   bypassesSampling(in.Event, in.StatusCode),
 		StatusCode: in.StatusCode,
 		Duration:   duration,
 		Level:      level,
 		Rate:       cfg.SamplingRate,
 		Event:      in.Event,
-	}) {
+		Header:     in.Header,
+
+		RequestSize:  in.RequestBodySize,
+		ResponseSize: in.ResponseBodySize,
+	})
+}
+
+// attachBodies records captured bodies on errored or explicitly kept events.
+func attachBodies(cfg hc.BodyCaptureConfig, in FinalizeInput, hasError bool) {
+	if decision, _ := hc.EventForcedDecision(in.Event); !hasError && decision != hc.ForceKeep {
+		return
+	}
+	attachBody(in.Ctx, cfg, "http.request.body", bodyOrRead(cfg, in.RequestBody, in.ReadRequestBody), headerValue(in.Header, "Content-Type"))
+	attachBody(in.Ctx, cfg, "http.response.body", bodyOrRead(cfg, in.ResponseBody, in.ReadResponseBody), headerValue(in.ResponseHeader, "Content-Type"))
+}
+
+// bodyOrRead returns body, or a buffer filled from read when body is nil.
+func bodyOrRead(cfg hc.BodyCaptureConfig, body *BodyBuffer, read func() []byte) *BodyBuffer {
+	if body != nil || read == nil {
+		return body
+	}
+	body = &BodyBuffer{limit: cfg.MaxBytes}
+	_, _ = body.Write(read())
+	return body
+}
+
+func headerValue(get func(string) string, name string) string {
+	if get == nil {
+		return ""
+	}
+	return get(name)
+}
+
+func writeEvent(cfg hc.Config, level hc.Level, in sampleInput) {
+	var fields map[string]any
+	if finalizers := hc.EventFinalizers(in.Event); len(finalizers) > 0 {
+		fields = eventFields(cfg, in.Event)
+		for _, fn := range finalizers {
+			fn(level, fields)
+		}
+	}
+
+	keep, rate := shouldWriteEvent(cfg, in)
+	if !keep {
 		return
 	}
 
@@ -63,32 +246,94 @@ func FinalizeRequest(cfg hc.Config, in FinalizeInput) {
 		msg = hc.EventMessage(in.Event)
 	}
 
-	cfg.Sink.Write(level, msg, hc.EventFields(in.Event))
+	if fs, ok := cfg.Sink.(hc.FieldSink); ok && fields == nil && cfg.Redactor == nil {
+		bufPtr := fieldListPool.Get().(*[]hc.Field)
+		list := hc.EventAppendFieldList(in.Event, (*bufPtr)[:0])
+		list = putSampleRate(list, rate)
+		fs.WriteFields(level, msg, list)
+		clear(list)
+		*bufPtr = list[:0]
+		fieldListPool.Put(bufPtr)
+		return
+	}
+	if fields == nil {
+		fields = eventFields(cfg, in.Event)
+	}
+	if fields == nil {
+		fields = make(map[string]any, 1)
+	}
+	if rate == 1 {
+		fields["sample_rate"] = boxedRateOne
+	} else {
+		fields["sample_rate"] = rate
+	}
+	cfg.Sink.Write(level, msg, fields)
+}
+
+// putSampleRate sets sample_rate in list, replacing a field of that name
+// recorded by the handler so sinks see the key once, as in the map form.
+func putSampleRate(list []hc.Field, rate float64) []hc.Field {
+	f := hc.Float64("sample_rate", rate)
+	for i := range list {
+		if list[i].Key == f.Key {
+			list[i] = f
+			return list
+		}
+	}
+	return append(list, f)
 }
 
-func annotateFailures(ctx context.Context, err error, recovered any) {
+var fieldListPool = sync.Pool{
+	New: func() any {
+		buf := make([]hc.Field, 0, 32)
+		return &buf
+	},
+}
+
+// boxedRateOne is the sample_rate of every kept error and full-rate event,
+// boxed once so the map path does not allocate for it.
+var boxedRateOne any = 1.0
+
+// eventFields returns a redacted snapshot owned by the caller.
+func eventFields(cfg hc.Config, event *hc.Event) map[string]any {
+	return cfg.Redactor.Redact(hc.EventFields(event))
+}
+
+// annotateFailures records a recovered panic and the error the handler
+// returned. A returned error without a class of its own is a client error
+// when it resolved to a status below 500, such as a framework's 4xx error.
+func annotateFailures(ctx context.Context, stack hc.PanicStackConfig, err error, recovered any, statusCode int) {
 	if recovered != nil {
-		hc.Add(ctx, "panic", map[string]any{
+		info := map[string]any{
 			"type":  fmt.Sprintf("%T", recovered),
 			"value": fmt.Sprint(recovered),
-		})
+		}
+		if stack.Enabled {
+			info["stack"] = panicStack(stack)
+		}
+		hc.Add(ctx, "panic", info)
 		hc.Error(ctx, fmt.Errorf("panic: %v", recovered))
 	}
 	if err != nil {
-		hc.Error(ctx, err)
+		class, ok := hc.ClassifyError(err)
+		if !ok && statusCode < 500 {
+			class = hc.ErrorClassClient
+		}
+		hc.Error(ctx, err, class)
 	}
 }
 
 func annotateTiming(ctx context.Context, event *hc.Event, statusCode int) time.Duration {
 	duration := time.Since(hc.EventStartTime(event))
-	hc.Add(ctx, "duration_ms", duration.Milliseconds(), "http.status", statusCode)
+	hc.AddFields(ctx, hc.Int64("duration_ms", duration.Milliseconds()), hc.Int("http.status", statusCode))
 	return duration
 }
 
-func resolveLevel(ctx context.Context, hasError bool) hc.Level {
-	autoLevel := hc.LevelInfo
-	if hasError {
-		autoLevel = hc.LevelError
+// resolveLevel raises autoLevel to errLevel, when set, then applies the
+// level requested with hc.SetLevel as a floor.
+func resolveLevel(ctx context.Context, autoLevel, errLevel hc.Level) hc.Level {
+	if errLevel != "" && levelRank(errLevel) > levelRank(autoLevel) {
+		autoLevel = errLevel
 	}
 	requestedLevel, hasRequestedLevel := hc.GetLevel(ctx)
 	return MergeLevelWithFloor(autoLevel, requestedLevel, hasRequestedLevel)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"reflect"
 	"testing"
 
 	hc "github.com/happytoolin/happycontext"
@@ -23,6 +24,79 @@ func TestStartRequestAddsBaseFields(t *testing.T) {
 	}
 }
 
+func TestStartRequestWithConfigRequestID(t *testing.T) {
+	cfg := NormalizeConfig(hc.Config{RequestID: hc.RequestIDConfig{
+		Enabled:   true,
+		Headers:   []string{"X-Request-ID", "X-Correlation-ID"},
+		Generator: func() string { return "generated" },
+	}})
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{name: "first header", headers: map[string]string{"X-Request-ID": "a", "X-Correlation-ID": "b"}, want: "a"},
+		{name: "fallback header", headers: map[string]string{"X-Correlation-ID": "b"}, want: "b"},
+		{name: "generated", want: "generated"},
+		{name: "invalid incoming", headers: map[string]string{"X-Request-ID": "bad id\n"}, want: "generated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			echoed := map[string]string{}
+			ctx, event := StartRequestWithConfig(cfg, StartInput{
+				Ctx:       context.Background(),
+				Method:    "GET",
+				Path:      "/x",
+				Header:    func(name string) string { return tt.headers[name] },
+				SetHeader: func(name, value string) { echoed[name] = value },
+			})
+			if got := hc.RequestID(ctx); got != tt.want {
+				t.Fatalf("request ID = %q, want %q", got, tt.want)
+			}
+			if hc.EventFields(event)["request_id"] != tt.want {
+				t.Fatalf("request_id field = %v", hc.EventFields(event)["request_id"])
+			}
+			if echoed["X-Request-ID"] != tt.want {
+				t.Fatalf("echoed headers = %v", echoed)
+			}
+		})
+	}
+
+	ctx, _ := StartRequestWithConfig(NormalizeConfig(hc.Config{}), StartInput{Ctx: context.Background()})
+	if hc.RequestID(ctx) != "" {
+		t.Fatal("expected no request ID when disabled")
+	}
+}
+
+func TestStartRequestWithConfigMergesSensitiveHeaders(t *testing.T) {
+	cfg := NormalizeConfig(hc.Config{Headers: hc.HeaderConfig{
+		Request:   []string{"Authorization", "X-Api-Key", "X-Tenant"},
+		Sensitive: []string{"X-Api-Key"},
+	}})
+	headers := map[string]string{"authorization": "Bearer secret", "x-api-key": "k1", "x-tenant": "acme"}
+
+	_, event := StartRequestWithConfig(cfg, StartInput{
+		Ctx:    context.Background(),
+		Method: "GET",
+		Path:   "/orders",
+		Header: func(name string) string { return headers[name] },
+	})
+
+	fields := hc.EventFields(event)
+	want := map[string]any{
+		"http.request.header.authorization": "[REDACTED]",
+		"http.request.header.x-api-key":     "[REDACTED]",
+		"http.request.header.x-tenant":      "acme",
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Fatalf("%s = %v, want %v", key, fields[key], value)
+		}
+	}
+}
+
 func TestFinalizeRequestEarlyReturnGuards(t *testing.T) {
 	ctx, event := StartRequest(context.Background(), "GET", "/x")
 	sink := hc.NewTestSink()
@@ -54,6 +128,93 @@ func TestFinalizeRequestRespectsSamplingDrop(t *testing.T) {
 	}
 }
 
+func TestFinalizeRequestRecordsSamplerRate(t *testing.T) {
+	ctx, event := StartRequest(context.Background(), "GET", "/x")
+	sink := hc.NewTestSink()
+	cfg := NormalizeConfig(hc.Config{
+		Sink: sink,
+		Sampler: hc.FromResultSampler(func(hc.SampleInput) hc.SampleResult {
+			return hc.SampleResult{Keep: true, Rate: 0.2}
+		}),
+	})
+
+	FinalizeRequest(cfg, FinalizeInput{Ctx: ctx, Event: event, Method: "GET", Path: "/x", StatusCode: 200})
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if events[0].Fields["sample_rate"] != 0.2 {
+		t.Fatalf("sample_rate = %v, want 0.2", events[0].Fields["sample_rate"])
+	}
+}
+
+func TestFinalizeRequestRunsFinalizersForDroppedEvents(t *testing.T) {
+	ctx, event := StartRequest(context.Background(), "GET", "/x")
+	sink := hc.NewTestSink()
+	cfg := NormalizeConfig(hc.Config{Sink: sink, SamplingRate: 0})
+
+	var gotLevel hc.Level
+	var gotFields map[string]any
+	hc.OnFinalize(ctx, func(level hc.Level, fields map[string]any) {
+		gotLevel, gotFields = level, fields
+	})
+
+	FinalizeRequest(cfg, FinalizeInput{Ctx: ctx, Event: event, Method: "GET", Path: "/x", StatusCode: 404})
+
+	if len(sink.Events()) != 0 {
+		t.Fatal("expected event to be sampled out")
+	}
+	if gotLevel != hc.LevelInfo {
+		t.Fatalf("finalizer level = %q, want %q", gotLevel, hc.LevelInfo)
+	}
+	if gotFields["http.status"] != 404 {
+		t.Fatalf("finalizer fields = %v", gotFields)
+	}
+}
+
+func TestFinalizeRequestRedactsBeforeSinkAndFinalizers(t *testing.T) {
+	ctx, event := StartRequest(context.Background(), "POST", "/signup")
+	sink := hc.NewTestSink()
+	cfg := NormalizeConfig(hc.Config{
+		Sink:         sink,
+		SamplingRate: 1,
+		Redactor: hc.NewRedactor(hc.RedactorOptions{Rules: []hc.RedactRule{
+			{Key: hc.MatchKey("password"), Action: hc.RedactDrop},
+			{Value: hc.DetectEmail()},
+		}}),
+	})
+	profile := map[string]any{"email": "new@example.com"}
+	hc.Add(ctx, "password", "hunter2", "profile", profile)
+
+	var finalized map[string]any
+	hc.OnFinalize(ctx, func(_ hc.Level, fields map[string]any) { finalized = fields })
+
+	FinalizeRequest(cfg, FinalizeInput{Ctx: ctx, Event: event, Method: "POST", Path: "/signup", StatusCode: 201})
+
+	fields := onlyCaptured(t, sink).Fields
+	for name, got := range map[string]map[string]any{"sink": fields, "finalizer": finalized} {
+		if _, ok := got["password"]; ok {
+			t.Fatalf("%s saw password field", name)
+		}
+		if got["profile"].(map[string]any)["email"] != "[REDACTED]" {
+			t.Fatalf("%s profile = %v", name, got["profile"])
+		}
+	}
+	if profile["email"] != "new@example.com" {
+		t.Fatal("redaction mutated the handler's map")
+	}
+}
+
+func onlyCaptured(t *testing.T, sink *hc.TestSink) hc.CapturedEvent {
+	t.Helper()
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	return events[0]
+}
+
 func TestFinalizeRequestMarksErrorAndRoute(t *testing.T) {
 	ctx, event := StartRequest(context.Background(), "POST", "/payments")
 	sink := hc.NewTestSink()
@@ -65,7 +226,7 @@ func TestFinalizeRequestMarksErrorAndRoute(t *testing.T) {
 		Method:     "POST",
 		Path:       "/payments",
 		Route:      "/payments/:id",
-		StatusCode: 200,
+		StatusCode: 500,
 		Err:        errors.New("handler failed"),
 	})
 
@@ -79,11 +240,53 @@ func TestFinalizeRequestMarksErrorAndRoute(t *testing.T) {
 	if events[0].Fields["http.route"] != "/payments/:id" {
 		t.Fatalf("route = %v", events[0].Fields["http.route"])
 	}
+	if events[0].Fields["sample_rate"] != 1.0 {
+		t.Fatalf("sample_rate = %v, want 1", events[0].Fields["sample_rate"])
+	}
 	if _, ok := events[0].Fields["error"].(map[string]any); !ok {
 		t.Fatal("expected structured error field")
 	}
 }
 
+func TestFinalizeRequestClassifiesReturnedErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		class  string
+		level  hc.Level
+	}{
+		{name: "4xx", err: errors.New("invalid email"), status: 400, class: "client", level: hc.LevelWarn},
+		{name: "5xx", err: errors.New("handler failed"), status: 500, class: "server", level: hc.LevelError},
+		{name: "canceled", err: context.Canceled, status: 499, class: "canceled", level: hc.LevelInfo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, event := StartRequest(context.Background(), "GET", "/orders")
+			sink := hc.NewTestSink()
+			FinalizeRequest(NormalizeConfig(hc.Config{Sink: sink, SamplingRate: 1}), FinalizeInput{
+				Ctx:        ctx,
+				Event:      event,
+				Method:     "GET",
+				Path:       "/orders",
+				StatusCode: tt.status,
+				Err:        tt.err,
+			})
+
+			events := sink.Events()
+			if len(events) != 1 {
+				t.Fatalf("expected 1 event, got %d", len(events))
+			}
+			if got := events[0].Fields["error"].(map[string]any)["class"]; got != tt.class {
+				t.Fatalf("error.class = %v, want %s", got, tt.class)
+			}
+			if events[0].Level != tt.level {
+				t.Fatalf("level = %s, want %s", events[0].Level, tt.level)
+			}
+		})
+	}
+}
+
 func TestFinalizeRequestPanicAddsMetadata(t *testing.T) {
 	ctx, event := StartRequest(context.Background(), "GET", "/panic")
 	sink := hc.NewTestSink()
@@ -196,3 +399,107 @@ func TestResolveStatus(t *testing.T) {
 		t.Fatalf("status = %d, want %d", got, http.StatusInternalServerError)
 	}
 }
+
+func TestStartRequestWithConfigAppliesErrorOptions(t *testing.T) {
+	cfg := NormalizeConfig(hc.Config{
+		ErrorEncoder: func(_ error, entry map[string]any) { entry["code"] = "E1" },
+	})
+	ctx, event := StartRequestWithConfig(cfg, StartInput{Ctx: context.Background(), Method: "GET", Path: "/x"})
+	plainCtx, plain := StartRequest(context.Background(), "GET", "/x")
+
+	hc.Error(ctx, errors.New("boom"))
+	hc.Error(plainCtx, errors.New("boom"))
+
+	if code := hc.EventFields(event)["error"].(map[string]any)["code"]; code != "E1" {
+		t.Fatalf("expected encoder from config, got code %v", code)
+	}
+	if _, ok := hc.EventFields(plain)["error"].(map[string]any)["code"]; ok {
+		t.Fatal("did not expect encoder on event started without config")
+	}
+}
+
+func TestFinalizeRequestWritesTypedFieldsToFieldSink(t *testing.T) {
+	ctx, event := StartRequest(context.Background(), "GET", "/x")
+	sink := &fieldSink{}
+	cfg := NormalizeConfig(hc.Config{Sink: sink, SamplingRate: 1})
+
+	FinalizeRequest(cfg, FinalizeInput{Ctx: ctx, Event: event, Method: "GET", Path: "/x", StatusCode: 201})
+
+	if sink.writes != 0 || sink.fieldWrites != 1 {
+		t.Fatalf("expected one WriteFields call, got writes=%d fieldWrites=%d", sink.writes, sink.fieldWrites)
+	}
+	byKey := make(map[string]hc.Field, len(sink.fields))
+	for _, f := range sink.fields {
+		byKey[f.Key] = f
+	}
+	if f := byKey["http.status"]; f.Kind() != hc.FieldKindInt || f.Int64Value() != 201 {
+		t.Fatalf("expected typed http.status, got %#v", f)
+	}
+	if f := byKey["http.method"]; f.Kind() != hc.FieldKindString || f.StringValue() != "GET" {
+		t.Fatalf("expected typed http.method, got %#v", f)
+	}
+	if f := byKey["sample_rate"]; f.Kind() != hc.FieldKindFloat64 || f.Float64Value() != 1 {
+		t.Fatalf("expected sample_rate 1, got %#v", f)
+	}
+}
+
+func TestFinalizeRequestSinkPathsWriteSameFields(t *testing.T) {
+	finalize := func(sink hc.Sink) {
+		ctx, event := StartRequest(context.Background(), "GET", "/x")
+		hc.Add(ctx, "sample_rate", "handler", "user.id", 7)
+		hc.AddFields(ctx, hc.Bool("cache.hit", true))
+		cfg := NormalizeConfig(hc.Config{Sink: sink, SamplingRate: 1})
+		FinalizeRequest(cfg, FinalizeInput{Ctx: ctx, Event: event, Method: "GET", Path: "/x", StatusCode: 200})
+	}
+
+	fs := &fieldSink{}
+	finalize(fs)
+	ms := hc.NewTestSink()
+	finalize(ms)
+
+	fromFields := make(map[string]any, len(fs.fields))
+	for _, f := range fs.fields {
+		if _, dup := fromFields[f.Key]; dup {
+			t.Fatalf("duplicate field %q in WriteFields", f.Key)
+		}
+		fromFields[f.Key] = f.Value()
+	}
+	events := ms.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected one map event, got %d", len(events))
+	}
+	fromMap := events[0].Fields
+	delete(fromFields, "duration_ms")
+	delete(fromMap, "duration_ms")
+	if !reflect.DeepEqual(fromFields, fromMap) {
+		t.Fatalf("sink paths differ:\nfields: %v\nmap:    %v", fromFields, fromMap)
+	}
+	if fromMap["sample_rate"] != 1.0 {
+		t.Fatalf("sample_rate = %v, want 1", fromMap["sample_rate"])
+	}
+}
+
+func TestFinalizeRequestUsesWriteWhenRedacting(t *testing.T) {
+	ctx, event := StartRequest(context.Background(), "GET", "/x")
+	sink := &fieldSink{}
+	cfg := NormalizeConfig(hc.Config{Sink: sink, SamplingRate: 1, Redactor: hc.NewRedactor(hc.RedactorOptions{})})
+
+	FinalizeRequest(cfg, FinalizeInput{Ctx: ctx, Event: event, Method: "GET", Path: "/x", StatusCode: 200})
+
+	if sink.writes != 1 || sink.fieldWrites != 0 {
+		t.Fatalf("expected one Write call, got writes=%d fieldWrites=%d", sink.writes, sink.fieldWrites)
+	}
+}
+
+type fieldSink struct {
+	writes      int
+	fieldWrites int
+	fields      []hc.Field
+}
+
+func (s *fieldSink) Write(hc.Level, string, map[string]any) { s.writes++ }
+
+func (s *fieldSink) WriteFields(_ hc.Level, _ string, fields []hc.Field) {
+	s.fieldWrites++
+	s.fields = append(s.fields[:0], fields...)
+}
@@ -0,0 +1,77 @@
+package common
+
+import (
+	"runtime"
+	"strings"
+
+	"github.com/happytoolin/happycontext"
+)
+
+const (
+	defaultPanicStackFrames = 32
+	maxPanicStackDepth      = 256
+)
+
+// panicStack returns the stack of the panicking goroutine as frame records,
+// starting at the panic site. It must run inside the deferred call that
+// recovered the panic, while the panicking frames are still on the stack.
+func panicStack(cfg hc.PanicStackConfig) []any {
+	var pcs [maxPanicStackDepth]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var all []runtime.Frame
+	start := 0
+	for {
+		frame, more := frames.Next()
+		all = append(all, frame)
+		if frame.Function == "runtime.gopanic" {
+			start = len(all)
+		}
+		if !more {
+			break
+		}
+	}
+	if start > 0 {
+		// Skip runtime helpers between gopanic and the faulting frame,
+		// such as runtime.sigpanic for nil dereferences.
+		for start < len(all) && strings.HasPrefix(all[start].Function, "runtime.") {
+			start++
+		}
+	}
+
+	out := make([]any, 0, min(len(all)-start, cfg.MaxFrames))
+	for _, frame := range all[start:] {
+		if len(out) == cfg.MaxFrames {
+			break
+		}
+		if cfg.SkipStdlib && stdlibFunction(frame.Function) {
+			continue
+		}
+		out = append(out, map[string]any{
+			"function": trimPrefixes(frame.Function, cfg.TrimPrefixes),
+			"file":     trimPrefixes(frame.File, cfg.TrimPrefixes),
+			"line":     frame.Line,
+		})
+	}
+	return out
+}
+
+// stdlibFunction reports whether fn belongs to the standard library, whose
+// import paths have no dot in their first element.
+func stdlibFunction(fn string) bool {
+	first, _, found := strings.Cut(fn, "/")
+	if !found {
+		first, _, _ = strings.Cut(fn, ".")
+	}
+	return first != "main" && !strings.Contains(first, ".")
+}
+
+func trimPrefixes(s string, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if trimmed, ok := strings.CutPrefix(s, prefix); ok {
+			return strings.TrimPrefix(trimmed, "/")
+		}
+	}
+	return s
+}
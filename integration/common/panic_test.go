@@ -0,0 +1,108 @@
+package common
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/happytoolin/happycontext"
+)
+
+//go:noinline
+func panickingHandler() {
+	var m map[string]int
+	m["x"] = 1
+}
+
+func finalizePanic(t *testing.T, stack hc.PanicStackConfig) []any {
+	t.Helper()
+	ctx, event := StartRequest(context.Background(), "GET", "/panic")
+	sink := hc.NewTestSink()
+	cfg := NormalizeConfig(hc.Config{Sink: sink, SamplingRate: 1, PanicStack: stack})
+
+	func() {
+		defer func() {
+			recovered := recover()
+			FinalizeRequest(cfg, FinalizeInput{
+				Ctx:        ctx,
+				Event:      event,
+				Method:     "GET",
+				Path:       "/panic",
+				StatusCode: 500,
+				Recovered:  recovered,
+			})
+		}()
+		panickingHandler()
+	}()
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	info := events[0].Fields["panic"].(map[string]any)
+	frames, _ := info["stack"].([]any)
+	return frames
+}
+
+func TestPanicStackStartsAtPanicSite(t *testing.T) {
+	frames := finalizePanic(t, hc.PanicStackConfig{Enabled: true})
+	if len(frames) == 0 {
+		t.Fatal("expected stack frames")
+	}
+	top := frames[0].(map[string]any)
+	if fn, _ := top["function"].(string); !strings.HasSuffix(fn, ".panickingHandler") {
+		t.Fatalf("expected panic site first, got %#v", top)
+	}
+	if file, _ := top["file"].(string); !strings.HasSuffix(file, "panic_test.go") {
+		t.Fatalf("unexpected file: %#v", top)
+	}
+	if line, _ := top["line"].(int); line == 0 {
+		t.Fatalf("expected line number, got %#v", top)
+	}
+}
+
+func TestPanicStackDisabledByDefault(t *testing.T) {
+	if frames := finalizePanic(t, hc.PanicStackConfig{}); frames != nil {
+		t.Fatalf("did not expect stack, got %d frames", len(frames))
+	}
+}
+
+func TestPanicStackOptions(t *testing.T) {
+	frames := finalizePanic(t, hc.PanicStackConfig{
+		Enabled:      true,
+		MaxFrames:    3,
+		TrimPrefixes: []string{"github.com/happytoolin/happycontext"},
+		SkipStdlib:   true,
+	})
+	if len(frames) == 0 || len(frames) > 3 {
+		t.Fatalf("expected 1-3 frames, got %d", len(frames))
+	}
+	for _, f := range frames {
+		fn := f.(map[string]any)["function"].(string)
+		if strings.HasPrefix(fn, "testing.") || strings.HasPrefix(fn, "runtime.") {
+			t.Fatalf("expected stdlib frames to be skipped, got %q", fn)
+		}
+		if strings.HasPrefix(fn, "github.com/") {
+			t.Fatalf("expected trimmed function, got %q", fn)
+		}
+	}
+	if fn := frames[0].(map[string]any)["function"]; fn != "integration/common.panickingHandler" {
+		t.Fatalf("unexpected top frame: %v", fn)
+	}
+}
+
+func TestStdlibFunction(t *testing.T) {
+	tests := map[string]bool{
+		"runtime.goexit":                   true,
+		"net/http.(*conn).serve":           true,
+		"testing.tRunner":                  true,
+		"main.main":                        false,
+		"github.com/acme/app.(*Svc).Serve": false,
+		"example.com/app/internal.Handle":  false,
+	}
+	for fn, want := range tests {
+		if got := stdlibFunction(fn); got != want {
+			t.Fatalf("stdlibFunction(%q) = %v, want %v", fn, got, want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package common
+
+import (
+	"context"
+	"time"
+
+	hc "github.com/happytoolin/happycontext"
+)
+
+// FinalizeRPCInput contains RPC data required for finalization.
+type FinalizeRPCInput struct {
+	Ctx        context.Context
+	Event      *hc.Event
+	FullMethod string
+	// StatusCode is the HTTP status equivalent of the RPC outcome.
+	// Values >= 500 mark server failures and bypass sampling like HTTP 5xx.
+	StatusCode int
+	// Level is the automatic level derived from the RPC outcome.
+	// Invalid values fall back to INFO.
+	Level     hc.Level
+	Err       error
+	Recovered any
+	// Header optionally exposes request metadata to samplers.
+	Header func(string) string
+}
+
+// StartRPCInput contains RPC data required to start an event.
+type StartRPCInput struct {
+	Ctx     context.Context
+	System  string
+	Service string
+	Method  string
+	// Header returns a request metadata value, or "" when absent.
+	Header func(string) string
+	// SetHeader sets a response metadata value. It is called before the handler runs.
+	SetHeader func(name, value string)
+}
+
+// StartRPC initializes request context and base RPC fields.
+func StartRPC(baseCtx context.Context, system, service, method string) (context.Context, *hc.Event) {
+	return startRPC(baseCtx, hc.Config{}, system, service, method)
+}
+
+func startRPC(baseCtx context.Context, cfg hc.Config, system, service, method string) (context.Context, *hc.Event) {
+	if baseCtx == nil {
+		baseCtx = context.Background()
+	}
+	ctx, event := hc.NewContextWithConfig(baseCtx, cfg)
+	hc.AddFields(ctx, hc.String("rpc.system", system), hc.String("rpc.service", service), hc.String("rpc.method", method))
+	return ctx, event
+}
+
+// StartRPCWithConfig initializes request context and base RPC fields, then
+// applies config-driven start behavior such as request IDs.
+//
+// cfg must be normalized with NormalizeConfig.
+func StartRPCWithConfig(cfg hc.Config, in StartRPCInput) (context.Context, *hc.Event) {
+	ctx, event := startRPC(in.Ctx, cfg, in.System, in.Service, in.Method)
+	applyRequestID(ctx, cfg.RequestID, in.Header, in.SetHeader)
+	return ctx, event
+}
+
+// FinalizeRPC computes level/sampling for an RPC and writes the final snapshot.
+//
+// The full method is exposed to samplers as SampleInput.Path and
+// StatusCode is used for status-based sampling rules.
+func FinalizeRPC(cfg hc.Config, in FinalizeRPCInput) {
+	if cfg.Sink == nil || in.Event == nil || in.Ctx == nil {
+		return
+	}
+
+	annotateFailures(in.Ctx, cfg.PanicStack, in.Err, in.Recovered, in.StatusCode)
+
+	duration := time.Since(hc.EventStartTime(in.Event))
+	hc.AddFields(in.Ctx, hc.Int64("duration_ms", duration.Milliseconds()))
+
+	autoLevel := in.Level
+	if !isValidLevel(autoLevel) {
+		autoLevel = hc.LevelInfo
+	}
+	errLevel, _ := errorLevel(cfg, in.Event, in.StatusCode)
+	level := resolveLevel(in.Ctx, autoLevel, errLevel)
+	writeEvent(cfg, level, sampleInput{
+		Path:       in.FullMethod,
+		HasError:   bypassesSampling(in.Event, in.StatusCode),
+		StatusCode: in.StatusCode,
+		Duration:   duration,
+		Level:      level,
+		Rate:       cfg.SamplingRate,
+		Event:      in.Event,
+		Header:     in.Header,
+	})
+}
@@ -0,0 +1,104 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	hc "github.com/happytoolin/happycontext"
+)
+
+func TestStartRPCAddsBaseFields(t *testing.T) {
+	ctx, event := StartRPC(context.Background(), "grpc", "orders.v1.Orders", "Get")
+	if event == nil || ctx == nil {
+		t.Fatal("expected context and event")
+	}
+	fields := hc.EventFields(event)
+	if fields["rpc.system"] != "grpc" {
+		t.Fatalf("system field = %v", fields["rpc.system"])
+	}
+	if fields["rpc.service"] != "orders.v1.Orders" {
+		t.Fatalf("service field = %v", fields["rpc.service"])
+	}
+	if fields["rpc.method"] != "Get" {
+		t.Fatalf("method field = %v", fields["rpc.method"])
+	}
+}
+
+func TestFinalizeRPCUsesAutoLevel(t *testing.T) {
+	ctx, event := StartRPC(context.Background(), "grpc", "orders.v1.Orders", "Get")
+	sink := hc.NewTestSink()
+	cfg := NormalizeConfig(hc.Config{Sink: sink, SamplingRate: 1})
+
+	FinalizeRPC(cfg, FinalizeRPCInput{
+		Ctx:        ctx,
+		Event:      event,
+		FullMethod: "/orders.v1.Orders/Get",
+		StatusCode: http.StatusNotFound,
+		Level:      hc.LevelWarn,
+	})
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if events[0].Level != hc.LevelWarn {
+		t.Fatalf("level = %s, want WARN", events[0].Level)
+	}
+	if _, ok := events[0].Fields["http.status"]; ok {
+		t.Fatal("expected no http.status field on rpc events")
+	}
+	if _, ok := events[0].Fields["duration_ms"]; !ok {
+		t.Fatal("expected duration_ms field")
+	}
+}
+
+func TestFinalizeRPCServerFailureBypassesSampling(t *testing.T) {
+	ctx, event := StartRPC(context.Background(), "grpc", "orders.v1.Orders", "Get")
+	sink := hc.NewTestSink()
+	cfg := NormalizeConfig(hc.Config{Sink: sink, SamplingRate: 0})
+
+	FinalizeRPC(cfg, FinalizeRPCInput{
+		Ctx:        ctx,
+		Event:      event,
+		FullMethod: "/orders.v1.Orders/Get",
+		StatusCode: http.StatusServiceUnavailable,
+		Err:        errors.New("backend down"),
+	})
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if events[0].Level != hc.LevelError {
+		t.Fatalf("level = %s, want ERROR", events[0].Level)
+	}
+}
+
+func TestFinalizeRPCPassesFullMethodToSampler(t *testing.T) {
+	ctx, event := StartRPC(context.Background(), "grpc", "orders.v1.Orders", "Get")
+	sink := hc.NewTestSink()
+	var gotPath string
+	cfg := NormalizeConfig(hc.Config{
+		Sink: sink,
+		Sampler: func(in hc.SampleInput) bool {
+			gotPath = in.Path
+			return false
+		},
+	})
+
+	FinalizeRPC(cfg, FinalizeRPCInput{
+		Ctx:        ctx,
+		Event:      event,
+		FullMethod: "/orders.v1.Orders/Get",
+		StatusCode: http.StatusOK,
+	})
+
+	if gotPath != "/orders.v1.Orders/Get" {
+		t.Fatalf("sampler path = %q", gotPath)
+	}
+	if len(sink.Events()) != 0 {
+		t.Fatal("expected sampler to drop event")
+	}
+}
@@ -22,11 +22,25 @@ type sampleInput struct {
 	Level      hc.Level
 	Rate       float64
 	Event      *hc.Event
+	Header     func(string) string
+
+	RequestSize  int64
+	ResponseSize int64
 }
 
-func shouldWriteEvent(cfg hc.Config, in sampleInput) bool {
+// shouldWriteEvent reports whether the event should be written and the
+// sample rate it represents. Forced keeps, errors, and events kept by a
+// sampler that reported no rate have rate 1.
+func shouldWriteEvent(cfg hc.Config, in sampleInput) (bool, float64) {
+	switch decision, _ := hc.EventForcedDecision(in.Event); decision {
+	case hc.ForceKeep:
+		return true, 1
+	case hc.ForceDrop:
+		return false, 0
+	}
+
 	if cfg.Sampler != nil {
-		return cfg.Sampler(hc.SampleInput{
+		keep := cfg.Sampler(hc.SampleInput{
 			Method:     in.Method,
 			Path:       in.Path,
 			StatusCode: in.StatusCode,
@@ -34,11 +48,22 @@ func shouldWriteEvent(cfg hc.Config, in sampleInput) bool {
 			Level:      in.Level,
 			HasError:   in.HasError,
 			Event:      in.Event,
+			Header:     in.Header,
+
+			RequestSize:  in.RequestSize,
+			ResponseSize: in.ResponseSize,
 		})
+		if !keep {
+			return false, 0
+		}
+		if rate, ok := hc.EventSampleRate(in.Event); ok {
+			return true, rate
+		}
+		return true, 1
 	}
 
 	if in.HasError || in.StatusCode >= 500 {
-		return true
+		return true, 1
 	}
 
 	rate := in.Rate
@@ -47,7 +72,10 @@ func shouldWriteEvent(cfg hc.Config, in sampleInput) bool {
 			rate = levelRate
 		}
 	}
-	return shouldSample(rate)
+	if !shouldSample(rate) {
+		return false, 0
+	}
+	return true, min(rate, 1)
 }
 
 func shouldSample(rate float64) bool {
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -16,16 +17,16 @@ func TestSamplingDecisionRules(t *testing.T) {
 		Rate:       0,
 	}
 
-	if !shouldWriteEvent(hc.Config{}, sampleInput{HasError: true, StatusCode: 200}) {
+	if !shouldWrite(hc.Config{}, sampleInput{HasError: true, StatusCode: 200}) {
 		t.Fatal("expected hasError to force logging")
 	}
-	if !shouldWriteEvent(hc.Config{}, sampleInput{StatusCode: 500}) {
+	if !shouldWrite(hc.Config{}, sampleInput{StatusCode: 500}) {
 		t.Fatal("expected 5xx to force logging")
 	}
-	if shouldWriteEvent(hc.Config{}, base) {
+	if shouldWrite(hc.Config{}, base) {
 		t.Fatal("expected rate 0 healthy request to be dropped")
 	}
-	if !shouldWriteEvent(hc.Config{}, sampleInput{Rate: 1}) {
+	if !shouldWrite(hc.Config{}, sampleInput{Rate: 1}) {
 		t.Fatal("expected rate 1 to always log")
 	}
 }
@@ -36,10 +37,10 @@ func TestSamplingDecisionUsesLevelOverrides(t *testing.T) {
 		LevelSamplingRates: map[hc.Level]float64{hc.LevelWarn: 1},
 	}
 
-	if !shouldWriteEvent(cfg, sampleInput{StatusCode: 200, Level: hc.LevelWarn}) {
+	if !shouldWrite(cfg, sampleInput{StatusCode: 200, Level: hc.LevelWarn}) {
 		t.Fatal("expected warn-level override to force logging")
 	}
-	if shouldWriteEvent(cfg, sampleInput{StatusCode: 200, Level: hc.LevelInfo}) {
+	if shouldWrite(cfg, sampleInput{StatusCode: 200, Level: hc.LevelInfo}) {
 		t.Fatal("expected info level to use default rate")
 	}
 }
@@ -51,13 +52,13 @@ func TestSamplingDecisionUsesCustomSampler(t *testing.T) {
 		},
 	}
 
-	if !shouldWriteEvent(cfg, sampleInput{Path: "/x", Level: hc.LevelWarn}) {
+	if !shouldWrite(cfg, sampleInput{Path: "/x", Level: hc.LevelWarn}) {
 		t.Fatal("expected custom sampler to keep warn level")
 	}
-	if !shouldWriteEvent(cfg, sampleInput{Path: "/always", Level: hc.LevelInfo}) {
+	if !shouldWrite(cfg, sampleInput{Path: "/always", Level: hc.LevelInfo}) {
 		t.Fatal("expected custom sampler to keep /always")
 	}
-	if shouldWriteEvent(cfg, sampleInput{Path: "/x", Level: hc.LevelInfo}) {
+	if shouldWrite(cfg, sampleInput{Path: "/x", Level: hc.LevelInfo}) {
 		t.Fatal("expected custom sampler to drop unmatched events")
 	}
 }
@@ -85,3 +86,53 @@ func TestNextSampleFloat64Range(t *testing.T) {
 		}
 	}
 }
+
+func TestSamplingDecisionHonorsForcedDecisions(t *testing.T) {
+	keepCtx, keepEvent := hc.NewContext(context.Background())
+	hc.KeepEvent(keepCtx, "interesting")
+	dropCtx, dropEvent := hc.NewContext(context.Background())
+	hc.DropEvent(dropCtx, "noise")
+
+	never := hc.Config{Sampler: hc.NeverSampler()}
+	if !shouldWrite(never, sampleInput{StatusCode: 200, Event: keepEvent}) {
+		t.Fatal("expected forced keep to override sampler")
+	}
+	if !shouldWrite(hc.Config{}, sampleInput{StatusCode: 200, Rate: 0, Event: keepEvent}) {
+		t.Fatal("expected forced keep to override rate")
+	}
+	if shouldWrite(hc.Config{}, sampleInput{HasError: true, StatusCode: 500, Event: dropEvent}) {
+		t.Fatal("expected forced drop to override error bypass")
+	}
+}
+
+func TestSamplingDecisionReportsRate(t *testing.T) {
+	_, e := hc.NewContext(context.Background())
+	cfg := hc.Config{Sampler: hc.FromResultSampler(func(hc.SampleInput) hc.SampleResult {
+		return hc.SampleResult{Keep: true, Rate: 0.25}
+	})}
+	if keep, rate := shouldWriteEvent(cfg, sampleInput{Event: e}); !keep || rate != 0.25 {
+		t.Fatalf("custom sampler: keep=%v rate=%v", keep, rate)
+	}
+
+	if keep, rate := shouldWriteEvent(hc.Config{Sampler: hc.AlwaysSampler()}, sampleInput{}); !keep || rate != 1 {
+		t.Fatalf("unreported rate: keep=%v rate=%v", keep, rate)
+	}
+	if keep, rate := shouldWriteEvent(hc.Config{}, sampleInput{StatusCode: 500, Rate: 0.1}); !keep || rate != 1 {
+		t.Fatalf("error bypass: keep=%v rate=%v", keep, rate)
+	}
+	cfg = hc.Config{LevelSamplingRates: map[hc.Level]float64{hc.LevelWarn: 0.9999999}}
+	for range 100 {
+		if keep, rate := shouldWriteEvent(cfg, sampleInput{Level: hc.LevelWarn}); keep {
+			if rate != 0.9999999 {
+				t.Fatalf("level rate = %v", rate)
+			}
+			return
+		}
+	}
+	t.Fatal("expected a kept event")
+}
+
+func shouldWrite(cfg hc.Config, in sampleInput) bool {
+	keep, _ := shouldWriteEvent(cfg, in)
+	return keep
+}
@@ -22,8 +22,32 @@ func Middleware(cfg hc.Config) echo.MiddlewareFunc {
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) (err error) {
-			ctx, event := common.StartRequest(c.Request().Context(), c.Request().Method, c.Request().URL.Path)
-			c.SetRequest(c.Request().WithContext(ctx))
+			if common.Excluded(cfg.Exclude, c.Request().URL.Path) {
+				return next(c)
+			}
+
+			ctx, event := common.StartRequestWithConfig(cfg, common.StartInput{
+				Ctx:       c.Request().Context(),
+				Method:    c.Request().Method,
+				Path:      c.Request().URL.Path,
+				Header:    c.Request().Header.Get,
+				SetHeader: c.Response().Header().Set,
+
+				RemoteAddr:   c.Request().RemoteAddr,
+				HeaderValues: c.Request().Header.Values,
+			})
+			req := c.Request().WithContext(ctx)
+			reqCapture := common.NewBodyBuffer(cfg)
+			body := common.CountBody(req.Body, reqCapture)
+			if body != nil {
+				req.Body = body
+			}
+			c.SetRequest(req)
+			respCapture := common.NewBodyBuffer(cfg)
+			if respCapture != nil {
+				res := c.Response()
+				res.Writer = &captureWriter{ResponseWriter: res.Writer, capture: respCapture}
+			}
 			var finalizeErr error
 
 			defer func() {
@@ -37,14 +61,21 @@ func Middleware(cfg hc.Config) echo.MiddlewareFunc {
 					statusFromEchoError(finalizeErr),
 				)
 				common.FinalizeRequest(cfg, common.FinalizeInput{
-					Ctx:        ctx,
-					Event:      event,
-					Method:     c.Request().Method,
-					Path:       c.Request().URL.Path,
-					Route:      route,
-					StatusCode: status,
-					Err:        finalizeErr,
-					Recovered:  recovered,
+					Ctx:            ctx,
+					Event:          event,
+					Method:         c.Request().Method,
+					Path:           c.Request().URL.Path,
+					Route:          route,
+					StatusCode:     status,
+					Err:            finalizeErr,
+					Recovered:      recovered,
+					Header:         c.Request().Header.Get,
+					ResponseHeader: c.Response().Header().Get,
+
+					RequestBodySize:  body.Size(),
+					ResponseBodySize: c.Response().Size,
+					RequestBody:      reqCapture,
+					ResponseBody:     respCapture,
 				})
 
 				if recovered != nil {
@@ -73,3 +104,20 @@ func statusFromEchoError(err error) int {
 	}
 	return http.StatusInternalServerError
 }
+
+// captureWriter copies written response bytes into a capture buffer.
+type captureWriter struct {
+	http.ResponseWriter
+	capture *common.BodyBuffer
+}
+
+func (w *captureWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	_, _ = w.capture.Write(p[:n])
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -2,9 +2,11 @@ package echohappycontext
 
 import (
 	"errors"
+	"io"
 	"maps"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 
@@ -43,6 +45,36 @@ func TestMiddlewareCapturesRouteAndFields(t *testing.T) {
 	}
 }
 
+func TestMiddlewareCapturesBodiesWhenKept(t *testing.T) {
+	e := echo.New()
+	sink := &memorySink{}
+	e.Use(Middleware(hc.Config{
+		Sink:         sink,
+		SamplingRate: 1,
+		BodyCapture:  hc.BodyCaptureConfig{Enabled: true},
+	}))
+	e.POST("/orders", func(c echo.Context) error {
+		hc.KeepEvent(c.Request().Context(), "debug")
+		_, _ = io.Copy(io.Discard, c.Request().Body)
+		return c.JSON(http.StatusOK, map[string]any{"ok": true})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"id":7}`))
+	req.Header.Set("Content-Type", "application/json")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if body, ok := events[0].Fields["http.request.body"].(map[string]any); !ok || body["id"] != float64(7) {
+		t.Fatalf("expected parsed request body, got %#v", events[0].Fields["http.request.body"])
+	}
+	if body, ok := events[0].Fields["http.response.body"].(map[string]any); !ok || body["ok"] != true {
+		t.Fatalf("expected parsed response body, got %#v", events[0].Fields["http.response.body"])
+	}
+}
+
 func TestMiddlewareSinkNilStillRunsHandler(t *testing.T) {
 	e := echo.New()
 	e.Use(Middleware(hc.Config{}))
@@ -149,8 +181,8 @@ func TestMiddlewareEchoHTTPErrorKeepsHTTPStatus(t *testing.T) {
 	if events[0].Fields["http.status"] != http.StatusForbidden {
 		t.Fatalf("status = %v, want %d", events[0].Fields["http.status"], http.StatusForbidden)
 	}
-	if events[0].Level != hc.LevelError {
-		t.Fatalf("level = %s, want ERROR", events[0].Level)
+	if events[0].Level != hc.LevelWarn {
+		t.Fatalf("level = %s, want WARN", events[0].Level)
 	}
 }
 
@@ -204,8 +236,8 @@ func TestMiddlewareLogsStatusFromCustomEchoErrorHandler(t *testing.T) {
 	if events[0].Fields["http.status"] != http.StatusTeapot {
 		t.Fatalf("status = %v, want %d", events[0].Fields["http.status"], http.StatusTeapot)
 	}
-	if events[0].Level != hc.LevelError {
-		t.Fatalf("level = %s, want ERROR", events[0].Level)
+	if events[0].Level != hc.LevelWarn {
+		t.Fatalf("level = %s, want WARN", events[0].Level)
 	}
 }
 
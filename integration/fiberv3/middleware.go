@@ -7,6 +7,7 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"github.com/happytoolin/happycontext"
 	"github.com/happytoolin/happycontext/integration/common"
+	"github.com/valyala/fasthttp"
 )
 
 // Middleware returns a Fiber v3 middleware that captures one event per request.
@@ -19,7 +20,20 @@ func Middleware(cfg hc.Config) fiber.Handler {
 	}
 
 	return func(c fiber.Ctx) (err error) {
-		ctx, event := common.StartRequest(c.Context(), c.Method(), c.Path())
+		if common.Excluded(cfg.Exclude, c.Path()) {
+			return c.Next()
+		}
+
+		ctx, event := common.StartRequestWithConfig(cfg, common.StartInput{
+			Ctx:       c.Context(),
+			Method:    c.Method(),
+			Path:      c.Path(),
+			Header:    func(name string) string { return c.Get(name) },
+			SetHeader: c.Set,
+
+			RemoteAddr:   c.RequestCtx().RemoteAddr().String(),
+			HeaderValues: func(name string) []string { return headerValues(&c.Request().Header, name) },
+		})
 		c.SetContext(ctx)
 		var finalizeErr error
 
@@ -33,14 +47,21 @@ func Middleware(cfg hc.Config) fiber.Handler {
 			responseStarted := status != 0 && (status != http.StatusOK || len(c.Response().Body()) > 0)
 			status = common.ResolveStatus(status, finalizeErr, recovered, responseStarted, statusFromFiberError(finalizeErr))
 			common.FinalizeRequest(cfg, common.FinalizeInput{
-				Ctx:        ctx,
-				Event:      event,
-				Method:     c.Method(),
-				Path:       c.Path(),
-				Route:      routePath,
-				StatusCode: status,
-				Err:        finalizeErr,
-				Recovered:  recovered,
+				Ctx:            ctx,
+				Event:          event,
+				Method:         c.Method(),
+				Path:           c.Path(),
+				Route:          routePath,
+				StatusCode:     status,
+				Err:            finalizeErr,
+				Recovered:      recovered,
+				Header:         func(name string) string { return c.Get(name) },
+				ResponseHeader: func(name string) string { return string(c.Response().Header.Peek(name)) },
+
+				RequestBodySize:  int64(len(c.Request().Body())),
+				ResponseBodySize: responseBodySize(c.Response()),
+				ReadRequestBody:  c.Request().Body,
+				ReadResponseBody: func() []byte { return bufferedResponseBody(c.Response()) },
 			})
 
 			if recovered != nil {
@@ -60,6 +81,33 @@ func Middleware(cfg hc.Config) fiber.Handler {
 	}
 }
 
+func headerValues(h *fasthttp.RequestHeader, name string) []string {
+	raw := h.PeekAll(name)
+	values := make([]string, len(raw))
+	for i, v := range raw {
+		values[i] = string(v)
+	}
+	return values
+}
+
+// responseBodySize reports the response body length without draining
+// streamed bodies, whose size is only known from Content-Length.
+func responseBodySize(resp *fasthttp.Response) int64 {
+	if resp.IsBodyStream() {
+		return int64(max(resp.Header.ContentLength(), 0))
+	}
+	return int64(len(resp.Body()))
+}
+
+// bufferedResponseBody returns a buffered response body. Streamed bodies
+// are skipped so capture never consumes them.
+func bufferedResponseBody(resp *fasthttp.Response) []byte {
+	if resp.IsBodyStream() {
+		return nil
+	}
+	return resp.Body()
+}
+
 func statusFromFiberError(err error) int {
 	if err == nil {
 		return 0
@@ -5,6 +5,7 @@ import (
 	"maps"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 
@@ -49,6 +50,37 @@ func TestMiddlewareCapturesRouteAndFields(t *testing.T) {
 	}
 }
 
+func TestMiddlewareCapturesBodiesWhenKept(t *testing.T) {
+	app := fiber.New()
+	sink := &memorySink{}
+	app.Use(Middleware(hc.Config{
+		Sink:         sink,
+		SamplingRate: 1,
+		BodyCapture:  hc.BodyCaptureConfig{Enabled: true},
+	}))
+	app.Post("/orders", func(c fiber.Ctx) error {
+		hc.KeepEvent(c.Context(), "debug")
+		return c.JSON(fiber.Map{"ok": true})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"id":7}`))
+	req.Header.Set("Content-Type", "application/json")
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("fiber test request failed: %v", err)
+	}
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if body, ok := events[0].Fields["http.request.body"].(map[string]any); !ok || body["id"] != float64(7) {
+		t.Fatalf("expected parsed request body, got %#v", events[0].Fields["http.request.body"])
+	}
+	if body, ok := events[0].Fields["http.response.body"].(map[string]any); !ok || body["ok"] != true {
+		t.Fatalf("expected parsed response body, got %#v", events[0].Fields["http.response.body"])
+	}
+}
+
 func TestMiddlewareSinkNilStillRunsHandler(t *testing.T) {
 	app := fiber.New()
 	app.Use(Middleware(hc.Config{}))
@@ -153,8 +185,8 @@ func TestMiddlewareFiberErrorKeepsHTTPStatus(t *testing.T) {
 	if events[0].Fields["http.status"] != http.StatusTooManyRequests {
 		t.Fatalf("status = %v, want %d", events[0].Fields["http.status"], http.StatusTooManyRequests)
 	}
-	if events[0].Level != hc.LevelError {
-		t.Fatalf("level = %s, want ERROR", events[0].Level)
+	if events[0].Level != hc.LevelWarn {
+		t.Fatalf("level = %s, want WARN", events[0].Level)
 	}
 }
 
@@ -212,8 +244,8 @@ func TestMiddlewareLogsStatusFromCustomFiberErrorHandler(t *testing.T) {
 	if events[0].Fields["http.status"] != http.StatusTeapot {
 		t.Fatalf("status = %v, want %d", events[0].Fields["http.status"], http.StatusTeapot)
 	}
-	if events[0].Level != hc.LevelError {
-		t.Fatalf("level = %s, want ERROR", events[0].Level)
+	if events[0].Level != hc.LevelWarn {
+		t.Fatalf("level = %s, want WARN", events[0].Level)
 	}
 }
 
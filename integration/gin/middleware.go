@@ -16,8 +16,31 @@ func Middleware(cfg hc.Config) gin.HandlerFunc {
 	}
 
 	return func(c *gin.Context) {
-		ctx, event := common.StartRequest(c.Request.Context(), c.Request.Method, c.Request.URL.Path)
+		if common.Excluded(cfg.Exclude, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		ctx, event := common.StartRequestWithConfig(cfg, common.StartInput{
+			Ctx:       c.Request.Context(),
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Header:    c.GetHeader,
+			SetHeader: c.Header,
+
+			RemoteAddr:   c.Request.RemoteAddr,
+			HeaderValues: c.Request.Header.Values,
+		})
 		c.Request = c.Request.WithContext(ctx)
+		reqCapture := common.NewBodyBuffer(cfg)
+		body := common.CountBody(c.Request.Body, reqCapture)
+		if body != nil {
+			c.Request.Body = body
+		}
+		respCapture := common.NewBodyBuffer(cfg)
+		if respCapture != nil {
+			c.Writer = &captureWriter{ResponseWriter: c.Writer, capture: respCapture}
+		}
 
 		defer func() {
 			recovered := recover()
@@ -27,14 +50,21 @@ func Middleware(cfg hc.Config) gin.HandlerFunc {
 			}
 			status := common.ResolveStatus(c.Writer.Status(), err, recovered, c.Writer.Written(), 0)
 			common.FinalizeRequest(cfg, common.FinalizeInput{
-				Ctx:        ctx,
-				Event:      event,
-				Method:     c.Request.Method,
-				Path:       c.Request.URL.Path,
-				Route:      c.FullPath(),
-				StatusCode: status,
-				Err:        err,
-				Recovered:  recovered,
+				Ctx:            ctx,
+				Event:          event,
+				Method:         c.Request.Method,
+				Path:           c.Request.URL.Path,
+				Route:          c.FullPath(),
+				StatusCode:     status,
+				Err:            err,
+				Recovered:      recovered,
+				Header:         c.GetHeader,
+				ResponseHeader: c.Writer.Header().Get,
+
+				RequestBodySize:  body.Size(),
+				ResponseBodySize: int64(max(c.Writer.Size(), 0)),
+				RequestBody:      reqCapture,
+				ResponseBody:     respCapture,
 			})
 
 			if recovered != nil {
@@ -45,3 +75,21 @@ func Middleware(cfg hc.Config) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// captureWriter copies written response bytes into a capture buffer.
+type captureWriter struct {
+	gin.ResponseWriter
+	capture *common.BodyBuffer
+}
+
+func (w *captureWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	_, _ = w.capture.Write(p[:n])
+	return n, err
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	n, err := w.ResponseWriter.WriteString(s)
+	_, _ = w.capture.Write([]byte(s[:n]))
+	return n, err
+}
@@ -2,9 +2,11 @@ package ginhappycontext
 
 import (
 	"errors"
+	"io"
 	"maps"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 
@@ -45,6 +47,38 @@ func TestMiddlewareCapturesRouteAndFields(t *testing.T) {
 	}
 }
 
+func TestMiddlewareCapturesBodiesWhenKept(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	sink := &memorySink{}
+	r := gin.New()
+	r.Use(Middleware(hc.Config{
+		Sink:         sink,
+		SamplingRate: 1,
+		BodyCapture:  hc.BodyCaptureConfig{Enabled: true},
+	}))
+	r.POST("/orders", func(c *gin.Context) {
+		hc.KeepEvent(c.Request.Context(), "debug")
+		_, _ = io.Copy(io.Discard, c.Request.Body)
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"id":7}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if body, ok := events[0].Fields["http.request.body"].(map[string]any); !ok || body["id"] != float64(7) {
+		t.Fatalf("expected parsed request body, got %#v", events[0].Fields["http.request.body"])
+	}
+	if body, ok := events[0].Fields["http.response.body"].(map[string]any); !ok || body["ok"] != true {
+		t.Fatalf("expected parsed response body, got %#v", events[0].Fields["http.response.body"])
+	}
+}
+
 func TestMiddlewareSinkNilStillRunsHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -182,8 +216,8 @@ func TestMiddlewareGinErrorKeepsCommittedStatus(t *testing.T) {
 	if events[0].Fields["http.status"] != http.StatusTooManyRequests {
 		t.Fatalf("status = %v, want %d", events[0].Fields["http.status"], http.StatusTooManyRequests)
 	}
-	if events[0].Level != hc.LevelError {
-		t.Fatalf("level = %s, want ERROR", events[0].Level)
+	if events[0].Level != hc.LevelWarn {
+		t.Fatalf("level = %s, want WARN", events[0].Level)
 	}
 }
 
@@ -0,0 +1,78 @@
+package gorillamuxhappycontext
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"weak"
+
+	"github.com/gorilla/mux"
+	"github.com/happytoolin/happycontext"
+	stdhappycontext "github.com/happytoolin/happycontext/integration/std"
+)
+
+type routeKey struct{}
+
+// Middleware returns gorilla/mux-compatible middleware that captures one
+// event per request and records the matched path template as http.route.
+//
+// It works both with Router.Use and wrapped around a *mux.Router. Router.Use
+// middleware only runs for matched routes, so wrap the router to also log
+// 404 and 405 responses. The first wrap of a router adds a Router.Use
+// middleware that reads the matched route and also exposes it to handlers as
+// r.Pattern; wrapping the same router again does not add another.
+func Middleware(cfg hc.Config) func(http.Handler) http.Handler {
+	mw := stdhappycontext.MiddlewareWithRoute(cfg, routeTemplate)
+	return func(next http.Handler) http.Handler {
+		h := mw(next)
+		router, ok := next.(*mux.Router)
+		if !ok {
+			return h
+		}
+		if _, loaded := routeRecorders.LoadOrStore(weak.Make(router), struct{}{}); !loaded {
+			router.Use(recordRoute)
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var route string
+			h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), routeKey{}, &route)))
+		})
+	}
+}
+
+// routeRecorders holds the routers recordRoute was added to. Keys are weak
+// so a registered router can still be collected.
+var routeRecorders sync.Map
+
+// recordRoute passes the matched path template out to a wrapping Middleware.
+// mux hands it a request of its own, so setting r.Pattern is not visible to
+// callers of the router.
+func recordRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tpl := currentTemplate(r); tpl != "" {
+			if route, ok := r.Context().Value(routeKey{}).(*string); ok {
+				*route = tpl
+			}
+			r.Pattern = tpl
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func routeTemplate(r *http.Request) string {
+	if route, ok := r.Context().Value(routeKey{}).(*string); ok && *route != "" {
+		return *route
+	}
+	if tpl := currentTemplate(r); tpl != "" {
+		return tpl
+	}
+	return r.Pattern
+}
+
+func currentTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			return tpl
+		}
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package gorillamuxhappycontext
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/happytoolin/happycontext"
+)
+
+func TestMiddlewareRecordsRouteWhenUsedInRouter(t *testing.T) {
+	sink := hc.NewTestSink()
+	r := mux.NewRouter()
+	r.Use(Middleware(hc.Config{Sink: sink, SamplingRate: 1}))
+	api := r.PathPrefix("/api").Subrouter()
+	api.HandleFunc("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		hc.Add(r.Context(), "order_id", mux.Vars(r)["id"])
+		w.WriteHeader(http.StatusAccepted)
+	}).Methods(http.MethodGet)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/orders/42", nil))
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if events[0].Fields["http.route"] != "/api/orders/{id}" {
+		t.Fatalf("expected route template, got %v", events[0].Fields["http.route"])
+	}
+	if events[0].Fields["order_id"] != "42" || events[0].Fields["http.status"] != http.StatusAccepted {
+		t.Fatalf("unexpected fields: %v", events[0].Fields)
+	}
+}
+
+func TestMiddlewareWrappingRouterLogsUnmatchedRequests(t *testing.T) {
+	sink := hc.NewTestSink()
+	r := mux.NewRouter()
+	r.HandleFunc("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.Pattern != "/orders/{id}" {
+			t.Errorf("expected pattern to be exposed to handler, got %q", r.Pattern)
+		}
+	})
+	h := Middleware(hc.Config{Sink: sink, SamplingRate: 1})(r)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	events := sink.Events()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].Fields["http.route"] != "/orders/{id}" {
+		t.Fatalf("expected route template, got %v", events[0].Fields["http.route"])
+	}
+	if _, ok := events[1].Fields["http.route"]; ok || events[1].Fields["http.status"] != http.StatusNotFound {
+		t.Fatalf("unexpected unmatched event: %v", events[1].Fields)
+	}
+}
+
+func TestMiddlewareWrappingRouterMatchesOnce(t *testing.T) {
+	sink := hc.NewTestSink()
+	r := mux.NewRouter()
+	matches := 0
+	r.HandleFunc("/orders/{id}", func(http.ResponseWriter, *http.Request) {}).
+		MatcherFunc(func(*http.Request, *mux.RouteMatch) bool {
+			matches++
+			return true
+		})
+	h := Middleware(hc.Config{Sink: sink, SamplingRate: 1})(r)
+	req := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if matches != 1 {
+		t.Fatalf("expected the route to be matched once, got %d", matches)
+	}
+	if req.Pattern != "" {
+		t.Fatalf("expected caller request unchanged, got Pattern %q", req.Pattern)
+	}
+	if events := sink.Events(); len(events) != 1 || events[0].Fields["http.route"] != "/orders/{id}" {
+		t.Fatalf("expected one event with route template, got %v", events)
+	}
+}
+
+func TestMiddlewareWrappingRouterTwiceRegistersOnce(t *testing.T) {
+	sink := hc.NewTestSink()
+	r := mux.NewRouter()
+	r.HandleFunc("/orders/{id}", func(http.ResponseWriter, *http.Request) {})
+	mw := Middleware(hc.Config{Sink: sink, SamplingRate: 1})
+	_ = mw(r)
+	h := mw(r)
+
+	// mux.Router does not expose its middleware, so count it by reflection.
+	if n := reflect.ValueOf(r).Elem().FieldByName("middlewares").Len(); n != 1 {
+		t.Fatalf("router middlewares = %d, want 1", n)
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+	if events := sink.Events(); len(events) != 1 || events[0].Fields["http.route"] != "/orders/{id}" {
+		t.Fatalf("expected one event with route template, got %v", events)
+	}
+}
+
+func TestMiddlewarePanicLogsAndPropagates(t *testing.T) {
+	sink := hc.NewTestSink()
+	r := mux.NewRouter()
+	r.Use(Middleware(hc.Config{Sink: sink, SamplingRate: 1}))
+	r.HandleFunc("/panic", func(http.ResponseWriter, *http.Request) {
+		panic(errors.New("boom"))
+	})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic to propagate")
+			}
+		}()
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+	}()
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if events[0].Level != hc.LevelError || events[0].Fields["http.status"] != http.StatusInternalServerError {
+		t.Fatalf("unexpected panic event: level=%s fields=%v", events[0].Level, events[0].Fields)
+	}
+	if events[0].Fields["http.route"] != "/panic" {
+		t.Fatalf("expected route template, got %v", events[0].Fields["http.route"])
+	}
+}
@@ -0,0 +1,228 @@
+package grpchappycontext
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/happytoolin/happycontext"
+	"github.com/happytoolin/happycontext/integration/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns a gRPC unary interceptor that captures one event per call.
+func UnaryServerInterceptor(cfg hc.Config) grpc.UnaryServerInterceptor {
+	cfg = common.NormalizeConfig(cfg)
+	if cfg.Sink == nil {
+		return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			return handler(ctx, req)
+		}
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		if common.Excluded(cfg.Exclude, info.FullMethod) {
+			return handler(ctx, req)
+		}
+		service, method := splitFullMethod(info.FullMethod)
+		ctx, event := common.StartRPCWithConfig(cfg, common.StartRPCInput{
+			Ctx:     ctx,
+			System:  "grpc",
+			Service: service,
+			Method:  method,
+			Header:  func(name string) string { return firstMetadataValue(ctx, name) },
+			SetHeader: func(name, value string) {
+				_ = grpc.SetHeader(ctx, metadata.Pairs(name, value))
+			},
+		})
+
+		defer func() {
+			recovered := recover()
+			var sent int64
+			if err == nil && recovered == nil {
+				sent = unaryMessages(resp)
+			}
+			finalize(ctx, cfg, event, info.FullMethod, err, recovered, unaryMessages(req), sent)
+
+			if recovered != nil {
+				panic(recovered)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC stream interceptor that captures one event per call.
+func StreamServerInterceptor(cfg hc.Config) grpc.StreamServerInterceptor {
+	cfg = common.NormalizeConfig(cfg)
+	if cfg.Sink == nil {
+		return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return handler(srv, ss)
+		}
+	}
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		if common.Excluded(cfg.Exclude, info.FullMethod) {
+			return handler(srv, ss)
+		}
+		service, method := splitFullMethod(info.FullMethod)
+		ctx, event := common.StartRPCWithConfig(cfg, common.StartRPCInput{
+			Ctx:     ss.Context(),
+			System:  "grpc",
+			Service: service,
+			Method:  method,
+			Header:  func(name string) string { return firstMetadataValue(ss.Context(), name) },
+			SetHeader: func(name, value string) {
+				_ = ss.SetHeader(metadata.Pairs(name, value))
+			},
+		})
+		stream := &serverStream{ServerStream: ss, ctx: ctx}
+
+		defer func() {
+			recovered := recover()
+			finalize(ctx, cfg, event, info.FullMethod, err, recovered, stream.received.Load(), stream.sent.Load())
+
+			if recovered != nil {
+				panic(recovered)
+			}
+		}()
+
+		return handler(srv, stream)
+	}
+}
+
+func finalize(ctx context.Context, cfg hc.Config, event *hc.Event, fullMethod string, err error, recovered any, received, sent int64) {
+	code := status.Code(err)
+	if recovered != nil {
+		code = codes.Internal
+	}
+	statusCode := httpStatusFromCode(code)
+
+	hc.AddFields(ctx,
+		hc.Int("rpc.grpc.status_code", int(code)),
+		hc.String("rpc.grpc.status", code.String()),
+		hc.Int64("rpc.messages_received", received),
+		hc.Int64("rpc.messages_sent", sent),
+	)
+
+	// Client-side codes are recorded as client (or canceled) errors, so they
+	// keep their WARN (or INFO) level and stay subject to sampling.
+	if err != nil && statusCode < 500 {
+		hc.Add(ctx, "rpc.grpc.status_message", status.Convert(err).Message())
+		class := hc.ErrorClassClient
+		if code == codes.Canceled {
+			class = hc.ErrorClassCanceled
+		}
+		hc.Error(ctx, err, class)
+	}
+
+	common.FinalizeRPC(cfg, common.FinalizeRPCInput{
+		Ctx:        ctx,
+		Event:      event,
+		FullMethod: fullMethod,
+		StatusCode: statusCode,
+		Level:      levelFromCode(code),
+		Err:        err,
+		Recovered:  recovered,
+		Header:     func(name string) string { return firstMetadataValue(ctx, name) },
+	})
+}
+
+// unaryMessages counts the message a unary call received or sent: grpc
+// decodes the request before interceptors run and sends the response only
+// when the handler returns one.
+func unaryMessages(m any) int64 {
+	if m == nil {
+		return 0
+	}
+	return 1
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	received atomic.Int64
+	sent     atomic.Int64
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent.Add(1)
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received.Add(1)
+	}
+	return err
+}
+
+func firstMetadataValue(ctx context.Context, name string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, name); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func splitFullMethod(fullMethod string) (string, string) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+func levelFromCode(code codes.Code) hc.Level {
+	switch code {
+	case codes.OK, codes.Canceled:
+		return hc.LevelInfo
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented,
+		codes.Internal, codes.Unavailable, codes.DataLoss:
+		return hc.LevelError
+	default:
+		return hc.LevelWarn
+	}
+}
+
+// httpStatusFromCode maps a gRPC code to its conventional HTTP status so
+// status-based sampling rules behave the same as for HTTP integrations.
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
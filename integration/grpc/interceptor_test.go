@@ -0,0 +1,298 @@
+package grpchappycontext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/happytoolin/happycontext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var unaryInfo = &grpc.UnaryServerInfo{FullMethod: "/orders.v1.Orders/Get"}
+
+func TestUnaryInterceptorLogsSuccess(t *testing.T) {
+	sink := hc.NewTestSink()
+	interceptor := UnaryServerInterceptor(hc.Config{Sink: sink, SamplingRate: 1, Message: "done"})
+
+	resp, err := interceptor(context.Background(), "req", unaryInfo, func(ctx context.Context, req any) (any, error) {
+		hc.Add(ctx, "order_id", "o_1")
+		return "resp", nil
+	})
+	if err != nil || resp != "resp" {
+		t.Fatalf("unexpected result: %v, %v", resp, err)
+	}
+
+	event := onlyEvent(t, sink)
+	if event.Level != hc.LevelInfo {
+		t.Fatalf("level = %s, want INFO", event.Level)
+	}
+	if event.Message != "done" {
+		t.Fatalf("message = %q, want done", event.Message)
+	}
+	if event.Fields["rpc.service"] != "orders.v1.Orders" || event.Fields["rpc.method"] != "Get" {
+		t.Fatalf("unexpected rpc fields: %v", event.Fields)
+	}
+	if event.Fields["rpc.grpc.status_code"] != int(codes.OK) {
+		t.Fatalf("status code = %v", event.Fields["rpc.grpc.status_code"])
+	}
+	if event.Fields["rpc.messages_received"] != int64(1) || event.Fields["rpc.messages_sent"] != int64(1) {
+		t.Fatalf("unexpected message counts: %v/%v", event.Fields["rpc.messages_received"], event.Fields["rpc.messages_sent"])
+	}
+	if event.Fields["order_id"] != "o_1" {
+		t.Fatalf("order_id = %v", event.Fields["order_id"])
+	}
+}
+
+func TestUnaryInterceptorMapsCodesToLevels(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantLevel hc.Level
+		wantClass string
+	}{
+		{name: "not found", err: status.Error(codes.NotFound, "missing"), wantLevel: hc.LevelWarn, wantClass: "client"},
+		{name: "canceled", err: status.Error(codes.Canceled, "gone"), wantLevel: hc.LevelInfo, wantClass: "canceled"},
+		{name: "internal", err: status.Error(codes.Internal, "boom"), wantLevel: hc.LevelError, wantClass: "server"},
+		{name: "plain error", err: errors.New("boom"), wantLevel: hc.LevelError, wantClass: "server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := hc.NewTestSink()
+			interceptor := UnaryServerInterceptor(hc.Config{Sink: sink, SamplingRate: 1})
+
+			_, err := interceptor(context.Background(), "req", unaryInfo, func(context.Context, any) (any, error) {
+				return nil, tt.err
+			})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected handler error to be returned, got %v", err)
+			}
+
+			event := onlyEvent(t, sink)
+			if event.Level != tt.wantLevel {
+				t.Fatalf("level = %s, want %s", event.Level, tt.wantLevel)
+			}
+			entry, ok := event.Fields["error"].(map[string]any)
+			if !ok || entry["message"] != tt.err.Error() {
+				t.Fatalf("expected error to be recorded, got %v", event.Fields["error"])
+			}
+			if entry["class"] != tt.wantClass {
+				t.Fatalf("error class = %v, want %s", entry["class"], tt.wantClass)
+			}
+			if event.Fields["rpc.messages_sent"] != int64(0) {
+				t.Fatalf("messages sent = %v, want 0", event.Fields["rpc.messages_sent"])
+			}
+		})
+	}
+}
+
+func TestUnaryInterceptorCountsMessages(t *testing.T) {
+	sink := hc.NewTestSink()
+	interceptor := UnaryServerInterceptor(hc.Config{Sink: sink, SamplingRate: 1})
+
+	_, _ = interceptor(context.Background(), nil, unaryInfo, func(context.Context, any) (any, error) {
+		return nil, nil
+	})
+
+	event := onlyEvent(t, sink)
+	if event.Fields["rpc.messages_received"] != int64(0) || event.Fields["rpc.messages_sent"] != int64(0) {
+		t.Fatalf("unexpected message counts: %v/%v", event.Fields["rpc.messages_received"], event.Fields["rpc.messages_sent"])
+	}
+}
+
+func TestUnaryInterceptorServerFaultBypassesSampling(t *testing.T) {
+	sink := hc.NewTestSink()
+	interceptor := UnaryServerInterceptor(hc.Config{Sink: sink, SamplingRate: 0})
+
+	_, _ = interceptor(context.Background(), "req", unaryInfo, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	})
+	if got := len(sink.Events()); got != 0 {
+		t.Fatalf("expected client-side code to be sampled out, got %d events", got)
+	}
+
+	_, _ = interceptor(context.Background(), "req", unaryInfo, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.Unavailable, "down")
+	})
+	event := onlyEvent(t, sink)
+	if event.Fields["rpc.grpc.status"] != codes.Unavailable.String() {
+		t.Fatalf("status = %v", event.Fields["rpc.grpc.status"])
+	}
+}
+
+func TestUnaryInterceptorRecordsAndRethrowsPanic(t *testing.T) {
+	sink := hc.NewTestSink()
+	interceptor := UnaryServerInterceptor(hc.Config{Sink: sink, SamplingRate: 1})
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic to be rethrown")
+		}
+		event := onlyEvent(t, sink)
+		if event.Level != hc.LevelError {
+			t.Fatalf("level = %s, want ERROR", event.Level)
+		}
+		if event.Fields["rpc.grpc.status_code"] != int(codes.Internal) {
+			t.Fatalf("status code = %v", event.Fields["rpc.grpc.status_code"])
+		}
+		if _, ok := event.Fields["panic"].(map[string]any); !ok {
+			t.Fatal("expected panic field")
+		}
+	}()
+
+	_, _ = interceptor(context.Background(), "req", unaryInfo, func(context.Context, any) (any, error) {
+		panic("boom")
+	})
+}
+
+func TestUnaryInterceptorNilSinkStillRunsHandler(t *testing.T) {
+	interceptor := UnaryServerInterceptor(hc.Config{})
+	called := false
+	_, _ = interceptor(context.Background(), "req", unaryInfo, func(ctx context.Context, _ any) (any, error) {
+		called = true
+		if hc.FromContext(ctx) != nil {
+			t.Fatal("expected no event without sink")
+		}
+		return nil, nil
+	})
+	if !called {
+		t.Fatal("expected handler to run")
+	}
+}
+
+func TestUnaryInterceptorSkipsExcludedMethods(t *testing.T) {
+	sink := hc.NewTestSink()
+	interceptor := UnaryServerInterceptor(hc.Config{
+		Sink:         sink,
+		SamplingRate: 1,
+		Exclude:      hc.ExcludeConfig{PathPrefixes: []string{"/grpc.health.v1.Health/"}},
+	})
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	_, _ = interceptor(context.Background(), "req", info, func(ctx context.Context, _ any) (any, error) {
+		if hc.FromContext(ctx) != nil {
+			t.Fatal("expected no event for excluded method")
+		}
+		return nil, status.Error(codes.Internal, "boom")
+	})
+	if len(sink.Events()) != 0 {
+		t.Fatalf("expected no events, got %d", len(sink.Events()))
+	}
+}
+
+func TestStreamInterceptorCountsMessages(t *testing.T) {
+	sink := hc.NewTestSink()
+	interceptor := StreamServerInterceptor(hc.Config{Sink: sink, SamplingRate: 1})
+	ss := &fakeServerStream{ctx: context.Background(), inbound: 2}
+
+	err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: "/orders.v1.Orders/Watch"}, func(_ any, stream grpc.ServerStream) error {
+		hc.Add(stream.Context(), "stream", true)
+		for {
+			if err := stream.RecvMsg(nil); err != nil {
+				break
+			}
+		}
+		for range 3 {
+			if err := stream.SendMsg("update"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	event := onlyEvent(t, sink)
+	if event.Fields["rpc.method"] != "Watch" {
+		t.Fatalf("method = %v", event.Fields["rpc.method"])
+	}
+	if event.Fields["rpc.messages_received"] != int64(2) {
+		t.Fatalf("messages received = %v, want 2", event.Fields["rpc.messages_received"])
+	}
+	if event.Fields["rpc.messages_sent"] != int64(3) {
+		t.Fatalf("messages sent = %v, want 3", event.Fields["rpc.messages_sent"])
+	}
+	if event.Fields["stream"] != true {
+		t.Fatal("expected handler field on stream event")
+	}
+}
+
+func TestSplitFullMethod(t *testing.T) {
+	service, method := splitFullMethod("/pkg.Service/Method")
+	if service != "pkg.Service" || method != "Method" {
+		t.Fatalf("split = %q, %q", service, method)
+	}
+	service, method = splitFullMethod("Method")
+	if service != "" || method != "Method" {
+		t.Fatalf("split = %q, %q", service, method)
+	}
+}
+
+func onlyEvent(t *testing.T, sink *hc.TestSink) hc.CapturedEvent {
+	t.Helper()
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	return events[0]
+}
+
+func TestStreamInterceptorPropagatesRequestID(t *testing.T) {
+	sink := hc.NewTestSink()
+	interceptor := StreamServerInterceptor(hc.Config{
+		Sink:         sink,
+		SamplingRate: 1,
+		RequestID:    hc.RequestIDConfig{Enabled: true},
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-42"))
+	ss := &fakeServerStream{ctx: ctx}
+
+	var seen string
+	err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: "/orders.v1.Orders/Watch"}, func(_ any, stream grpc.ServerStream) error {
+		seen = hc.RequestID(stream.Context())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if seen != "req-42" {
+		t.Fatalf("handler request ID = %q, want req-42", seen)
+	}
+	if got := onlyEvent(t, sink).Fields["request_id"]; got != "req-42" {
+		t.Fatalf("request_id = %v, want req-42", got)
+	}
+	if got := ss.header.Get("x-request-id"); len(got) != 1 || got[0] != "req-42" {
+		t.Fatalf("response header = %v", got)
+	}
+}
+
+type fakeServerStream struct {
+	ctx     context.Context
+	inbound int
+	header  metadata.MD
+}
+
+func (s *fakeServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *fakeServerStream) SendHeader(metadata.MD) error { return nil }
+func (s *fakeServerStream) SetTrailer(metadata.MD)       {}
+func (s *fakeServerStream) Context() context.Context     { return s.ctx }
+func (s *fakeServerStream) SendMsg(any) error            { return nil }
+
+func (s *fakeServerStream) RecvMsg(any) error {
+	if s.inbound == 0 {
+		return io.EOF
+	}
+	s.inbound--
+	return nil
+}
@@ -0,0 +1,16 @@
+module github.com/happytoolin/happycontext/integration/httprouter
+
+go 1.24
+
+require (
+	github.com/happytoolin/happycontext v0.2.4 // x-release-please-version
+	github.com/happytoolin/happycontext/integration/std v0.2.4 // x-release-please-version
+)
+
+require github.com/julienschmidt/httprouter v1.3.0
+
+require github.com/felixge/httpsnoop v1.0.4 // indirect
+
+replace github.com/happytoolin/happycontext => ../..
+
+replace github.com/happytoolin/happycontext/integration/std => ../std
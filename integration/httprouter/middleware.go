@@ -0,0 +1,128 @@
+package httprouterhappycontext
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/happytoolin/happycontext"
+	stdhappycontext "github.com/happytoolin/happycontext/integration/std"
+	"github.com/julienschmidt/httprouter"
+)
+
+type routeKey struct{}
+
+// Middleware returns middleware that captures one event per request.
+// Wrap a Router with it so the matched path is recorded as http.route.
+func Middleware(cfg hc.Config) func(http.Handler) http.Handler {
+	mw := stdhappycontext.MiddlewareWithRoute(cfg, matchedRoute)
+	return func(next http.Handler) http.Handler {
+		h := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Router.Handle fills route in on the request it receives, which
+			// it does not share with the handle.
+			var route string
+			h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), routeKey{}, &route)))
+		})
+	}
+}
+
+func matchedRoute(r *http.Request) string {
+	if route, ok := r.Context().Value(routeKey{}).(*string); ok && *route != "" {
+		return *route
+	}
+	return r.Pattern
+}
+
+// Router is an httprouter.Router whose registration methods record the
+// registered path as http.route and pass handles a copy of the request with
+// r.Pattern set to it, the way http.ServeMux does.
+//
+// Handles registered directly on the embedded httprouter.Router, such as
+// through r.Router.GET, are served without a route.
+type Router struct {
+	*httprouter.Router
+}
+
+// New returns a Router wrapping httprouter.New().
+func New() *Router {
+	return &Router{Router: httprouter.New()}
+}
+
+// Handle registers handle for method and path.
+func (r *Router) Handle(method, path string, handle httprouter.Handle) {
+	r.Router.Handle(method, path, func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		if route, ok := req.Context().Value(routeKey{}).(*string); ok {
+			*route = path
+		}
+		req = req.WithContext(req.Context())
+		req.Pattern = path
+		handle(w, req, ps)
+	})
+}
+
+// ServeFiles serves files from root under path, which must end with
+// "/*filepath", like httprouter.Router.ServeFiles.
+func (r *Router) ServeFiles(path string, root http.FileSystem) {
+	if !strings.HasSuffix(path, "/*filepath") {
+		panic("path must end with /*filepath in path '" + path + "'")
+	}
+	fileServer := http.FileServer(root)
+	r.GET(path, func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		u := *req.URL
+		u.Path = ps.ByName("filepath")
+		req.URL = &u
+		fileServer.ServeHTTP(w, req)
+	})
+}
+
+// Handler registers handler for method and path.
+// The Params are available in the request context under httprouter.ParamsKey.
+func (r *Router) Handler(method, path string, handler http.Handler) {
+	r.Handle(method, path, func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		if len(ps) > 0 {
+			req = req.WithContext(context.WithValue(req.Context(), httprouter.ParamsKey, ps))
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// HandlerFunc registers handler for method and path.
+func (r *Router) HandlerFunc(method, path string, handler http.HandlerFunc) {
+	r.Handler(method, path, handler)
+}
+
+// GET is a shortcut for r.Handle(http.MethodGet, path, handle).
+func (r *Router) GET(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodGet, path, handle)
+}
+
+// HEAD is a shortcut for r.Handle(http.MethodHead, path, handle).
+func (r *Router) HEAD(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodHead, path, handle)
+}
+
+// OPTIONS is a shortcut for r.Handle(http.MethodOptions, path, handle).
+func (r *Router) OPTIONS(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodOptions, path, handle)
+}
+
+// POST is a shortcut for r.Handle(http.MethodPost, path, handle).
+func (r *Router) POST(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodPost, path, handle)
+}
+
+// PUT is a shortcut for r.Handle(http.MethodPut, path, handle).
+func (r *Router) PUT(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodPut, path, handle)
+}
+
+// PATCH is a shortcut for r.Handle(http.MethodPatch, path, handle).
+func (r *Router) PATCH(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodPatch, path, handle)
+}
+
+// DELETE is a shortcut for r.Handle(http.MethodDelete, path, handle).
+func (r *Router) DELETE(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodDelete, path, handle)
+}
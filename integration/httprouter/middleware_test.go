@@ -0,0 +1,146 @@
+package httprouterhappycontext
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/happytoolin/happycontext"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestMiddlewareRecordsRoute(t *testing.T) {
+	sink := hc.NewTestSink()
+	router := New()
+	router.GET("/orders/:id", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		hc.Add(r.Context(), "order_id", ps.ByName("id"))
+		w.WriteHeader(http.StatusAccepted)
+	})
+	router.HandlerFunc(http.MethodGet, "/files/*path", func(_ http.ResponseWriter, r *http.Request) {
+		hc.Add(r.Context(), "file", httprouter.ParamsFromContext(r.Context()).ByName("path"))
+	})
+	h := Middleware(hc.Config{Sink: sink, SamplingRate: 1})(router)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/42", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/files/a/b.txt", nil))
+
+	events := sink.Events()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].Fields["http.route"] != "/orders/:id" || events[0].Fields["order_id"] != "42" {
+		t.Fatalf("unexpected handle event: %v", events[0].Fields)
+	}
+	if events[0].Fields["http.status"] != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %v", http.StatusAccepted, events[0].Fields["http.status"])
+	}
+	if events[1].Fields["http.route"] != "/files/*path" || events[1].Fields["file"] != "/a/b.txt" {
+		t.Fatalf("unexpected handler event: %v", events[1].Fields)
+	}
+}
+
+func TestMiddlewareNotFoundHasNoRoute(t *testing.T) {
+	sink := hc.NewTestSink()
+	router := New()
+	router.GET("/orders/:id", func(http.ResponseWriter, *http.Request, httprouter.Params) {})
+	h := Middleware(hc.Config{Sink: sink, SamplingRate: 1})(router)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if _, ok := events[0].Fields["http.route"]; ok || events[0].Fields["http.status"] != http.StatusNotFound {
+		t.Fatalf("unexpected unmatched event: %v", events[0].Fields)
+	}
+}
+
+func TestMiddlewarePanicLogsAndPropagates(t *testing.T) {
+	sink := hc.NewTestSink()
+	router := New()
+	router.POST("/panic", func(http.ResponseWriter, *http.Request, httprouter.Params) {
+		panic(errors.New("boom"))
+	})
+	h := Middleware(hc.Config{Sink: sink, SamplingRate: 1})(router)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic to propagate")
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/panic", nil))
+	}()
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if events[0].Level != hc.LevelError || events[0].Fields["http.status"] != http.StatusInternalServerError {
+		t.Fatalf("unexpected panic event: level=%s fields=%v", events[0].Level, events[0].Fields)
+	}
+	if events[0].Fields["http.route"] != "/panic" {
+		t.Fatalf("expected route template, got %v", events[0].Fields["http.route"])
+	}
+}
+
+func TestRouterDoesNotMutateCallerRequest(t *testing.T) {
+	router := New()
+	var pattern string
+	router.GET("/orders/:id", func(_ http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		pattern = r.Pattern
+	})
+	req := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if pattern != "/orders/:id" {
+		t.Fatalf("expected handle to see r.Pattern, got %q", pattern)
+	}
+	if req.Pattern != "" {
+		t.Fatalf("expected caller request unchanged, got Pattern %q", req.Pattern)
+	}
+}
+
+func TestRouterServeFilesRecordsRoute(t *testing.T) {
+	sink := hc.NewTestSink()
+	router := New()
+	router.ServeFiles("/static/*filepath", http.FS(fstest.MapFS{"app.js": {Data: []byte("ok")}}))
+	h := Middleware(hc.Config{Sink: sink, SamplingRate: 1})(router)
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/static/app.js", nil))
+
+	if rr.Code != http.StatusOK || rr.Body.String() != "ok" {
+		t.Fatalf("unexpected response: %d %q", rr.Code, rr.Body.String())
+	}
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if events[0].Fields["http.route"] != "/static/*filepath" || events[0].Fields["http.path"] != "/static/app.js" {
+		t.Fatalf("unexpected file event: %v", events[0].Fields)
+	}
+}
+
+func TestEmbeddedRouterRegistrationRecordsNoRoute(t *testing.T) {
+	sink := hc.NewTestSink()
+	router := New()
+	router.Router.GET("/raw/:id", func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	h := Middleware(hc.Config{Sink: sink, SamplingRate: 1})(router)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/raw/1", nil))
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if _, ok := events[0].Fields["http.route"]; ok || events[0].Fields["http.status"] != http.StatusNoContent {
+		t.Fatalf("unexpected embedded registration event: %v", events[0].Fields)
+	}
+}
@@ -0,0 +1,140 @@
+package otelhappycontext
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/happytoolin/happycontext"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Field names recorded on the event.
+const (
+	FieldTraceID    = "trace_id"
+	FieldSpanID     = "span_id"
+	FieldTraceFlags = "trace_flags"
+)
+
+type mirrorKey struct{}
+
+// Options controls trace context extraction and span mirroring.
+type Options struct {
+	// Propagator extracts a remote span context when ctx has no active span.
+	// Default is the W3C trace context propagator (traceparent header).
+	Propagator propagation.TextMapPropagator
+
+	// MirrorAttributes copies the event's final fields onto the active span
+	// as attributes when the event is finalized.
+	MirrorAttributes bool
+
+	// AttributePrefix is prepended to mirrored attribute keys.
+	AttributePrefix string
+}
+
+// Middleware returns net/http middleware that stamps trace context on the
+// request event.
+//
+// It must run inside the happycontext middleware so the event exists, and
+// inside any OpenTelemetry server instrumentation so the span is active.
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Annotate(r.Context(), propagation.HeaderCarrier(r.Header), opts)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Annotate stamps trace_id, span_id, and trace_flags on the event in ctx.
+//
+// The active span in ctx is used when present; otherwise the span context
+// is extracted from carrier, in which case span_id identifies the caller's
+// span. It returns false when ctx has no event or no valid span context.
+func Annotate(ctx context.Context, carrier propagation.TextMapCarrier, opts Options) bool {
+	if hc.FromContext(ctx) == nil {
+		return false
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if opts.MirrorAttributes && span.IsRecording() {
+		// Annotate may run more than once per request; mirror only once.
+		prefix := opts.AttributePrefix
+		hc.OnFinalizeOnce(ctx, mirrorKey{}, func(_ hc.Level, fields map[string]any) {
+			span.SetAttributes(Attributes(prefix, fields)...)
+		})
+	}
+
+	sc := span.SpanContext()
+	if !sc.IsValid() && carrier != nil {
+		propagator := opts.Propagator
+		if propagator == nil {
+			propagator = propagation.TraceContext{}
+		}
+		sc = trace.SpanContextFromContext(propagator.Extract(context.Background(), carrier))
+	}
+	if !sc.IsValid() {
+		return false
+	}
+
+	return hc.Add(ctx,
+		FieldTraceID, sc.TraceID().String(),
+		FieldSpanID, sc.SpanID().String(),
+		FieldTraceFlags, sc.TraceFlags().String(),
+	)
+}
+
+// Attributes converts event fields to span attributes.
+//
+// Nested maps are flattened with dotted keys, and values without a matching
+// attribute type are formatted with fmt.Sprint. Trace context fields are
+// skipped because the span already carries them.
+func Attributes(prefix string, fields map[string]any) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(fields))
+	for key, value := range fields {
+		switch key {
+		case FieldTraceID, FieldSpanID, FieldTraceFlags:
+			continue
+		}
+		attrs = appendAttribute(attrs, prefix+key, value)
+	}
+	return attrs
+}
+
+func appendAttribute(attrs []attribute.KeyValue, key string, value any) []attribute.KeyValue {
+	switch v := value.(type) {
+	case nil:
+		return attrs
+	case string:
+		return append(attrs, attribute.String(key, v))
+	case bool:
+		return append(attrs, attribute.Bool(key, v))
+	case int:
+		return append(attrs, attribute.Int(key, v))
+	case int32:
+		return append(attrs, attribute.Int64(key, int64(v)))
+	case int64:
+		return append(attrs, attribute.Int64(key, v))
+	case uint32:
+		return append(attrs, attribute.Int64(key, int64(v)))
+	case float32:
+		return append(attrs, attribute.Float64(key, float64(v)))
+	case float64:
+		return append(attrs, attribute.Float64(key, v))
+	case []string:
+		return append(attrs, attribute.StringSlice(key, v))
+	case map[string]any:
+		for k, nested := range v {
+			attrs = appendAttribute(attrs, key+"."+k, nested)
+		}
+		return attrs
+	case error:
+		return append(attrs, attribute.String(key, v.Error()))
+	case fmt.Stringer:
+		return append(attrs, attribute.String(key, v.String()))
+	default:
+		return append(attrs, attribute.String(key, fmt.Sprint(v)))
+	}
+}
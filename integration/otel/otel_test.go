@@ -0,0 +1,145 @@
+package otelhappycontext
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/happytoolin/happycontext"
+	"github.com/happytoolin/happycontext/integration/common"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestAnnotateUsesActiveSpan(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+	ctx, event := hc.NewContext(ctx)
+
+	if !Annotate(ctx, nil, Options{}) {
+		t.Fatal("expected trace context to be stamped")
+	}
+
+	sc := span.SpanContext()
+	fields := hc.EventFields(event)
+	if fields[FieldTraceID] != sc.TraceID().String() || fields[FieldSpanID] != sc.SpanID().String() {
+		t.Fatalf("unexpected trace fields: %v", fields)
+	}
+	if fields[FieldTraceFlags] != "01" {
+		t.Fatalf("trace_flags = %v, want 01", fields[FieldTraceFlags])
+	}
+}
+
+func TestAnnotateFallsBackToTraceparent(t *testing.T) {
+	ctx, event := hc.NewContext(context.Background())
+	header := http.Header{}
+	header.Set("traceparent", traceparent)
+
+	if !Annotate(ctx, propagation.HeaderCarrier(header), Options{}) {
+		t.Fatal("expected trace context from traceparent")
+	}
+
+	fields := hc.EventFields(event)
+	if fields[FieldTraceID] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("trace_id = %v", fields[FieldTraceID])
+	}
+	if fields[FieldSpanID] != "00f067aa0ba902b7" {
+		t.Fatalf("span_id = %v", fields[FieldSpanID])
+	}
+}
+
+func TestAnnotateWithoutTraceContext(t *testing.T) {
+	if Annotate(context.Background(), nil, Options{}) {
+		t.Fatal("expected false without event")
+	}
+
+	ctx, event := hc.NewContext(context.Background())
+	header := http.Header{}
+	header.Set("traceparent", "garbage")
+	if Annotate(ctx, propagation.HeaderCarrier(header), Options{}) {
+		t.Fatal("expected false for invalid traceparent")
+	}
+	if _, ok := hc.EventFields(event)[FieldTraceID]; ok {
+		t.Fatal("expected no trace fields")
+	}
+}
+
+func TestMiddlewareMirrorsFinalFieldsOntoSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	sink := hc.NewTestSink()
+	cfg := common.NormalizeConfig(hc.Config{Sink: sink, SamplingRate: 1})
+
+	handler := Middleware(Options{MirrorAttributes: true, AttributePrefix: "app."})(
+		http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			hc.Add(r.Context(), "user_id", "u_1", "attempts", 3)
+			hc.Error(r.Context(), errors.New("card declined"))
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/checkout", nil)
+	spanCtx, span := tp.Tracer("test").Start(req.Context(), "POST /checkout")
+	ctx, event := common.StartRequest(spanCtx, req.Method, req.URL.Path)
+	handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+	common.FinalizeRequest(cfg, common.FinalizeInput{
+		Ctx:        ctx,
+		Event:      event,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		StatusCode: http.StatusPaymentRequired,
+	})
+	span.End()
+
+	events := sink.Events()
+	if len(events) != 1 || events[0].Fields[FieldTraceID] != span.SpanContext().TraceID().String() {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if attrs["app.user_id"].AsString() != "u_1" {
+		t.Fatalf("user_id attribute = %v", attrs["app.user_id"])
+	}
+	if attrs["app.attempts"].AsInt64() != 3 {
+		t.Fatalf("attempts attribute = %v", attrs["app.attempts"])
+	}
+	if attrs["app.http.status"].AsInt64() != http.StatusPaymentRequired {
+		t.Fatalf("status attribute = %v", attrs["app.http.status"])
+	}
+	if attrs["app.error.message"].AsString() != "card declined" {
+		t.Fatalf("error attribute = %v", attrs["app.error.message"])
+	}
+	if _, ok := attrs["app."+FieldTraceID]; ok {
+		t.Fatal("expected trace fields not to be mirrored")
+	}
+}
+
+func TestAnnotateRegistersMirrorOnce(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	spanCtx, span := tp.Tracer("test").Start(context.Background(), "GET /orders")
+	defer span.End()
+	ctx, event := hc.NewContext(spanCtx)
+
+	opts := Options{MirrorAttributes: true}
+	for range 3 {
+		if !Annotate(ctx, nil, opts) {
+			t.Fatal("expected Annotate to stamp the active span")
+		}
+	}
+	if n := len(hc.EventFinalizers(event)); n != 1 {
+		t.Fatalf("expected one mirror finalizer, got %d", n)
+	}
+}
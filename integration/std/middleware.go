@@ -12,22 +12,50 @@ import (
 // Config controls standard net/http middleware behavior.
 type Config = hc.Config
 
+// RouteFunc resolves the route template of a request after the handler runs.
+type RouteFunc func(r *http.Request) string
+
 // Middleware wraps an http.Handler with happycontext request lifecycle logging.
+// The route is taken from r.Pattern, which http.ServeMux populates.
 func Middleware(cfg Config) func(http.Handler) http.Handler {
+	return MiddlewareWithRoute(cfg, patternRoute)
+}
+
+// MiddlewareWithRoute is like Middleware but resolves http.route with route,
+// for routers that do not populate r.Pattern. route is called with the
+// request passed to the handler once it returns.
+func MiddlewareWithRoute(cfg Config, route RouteFunc) func(http.Handler) http.Handler {
 	cfg = common.NormalizeConfig(cfg)
+	if route == nil {
+		route = patternRoute
+	}
 	sink := cfg.Sink
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if sink == nil {
+			if sink == nil || common.Excluded(cfg.Exclude, r.URL.Path) {
 				next.ServeHTTP(w, r)
 				return
 			}
 
-			ctx, event := common.StartRequest(r.Context(), r.Method, r.URL.Path)
+			ctx, event := common.StartRequestWithConfig(cfg, common.StartInput{
+				Ctx:       r.Context(),
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Header:    r.Header.Get,
+				SetHeader: w.Header().Set,
+
+				RemoteAddr:   r.RemoteAddr,
+				HeaderValues: r.Header.Values,
+			})
 
 			req := r.WithContext(ctx)
-			tracker := &responseWriter{}
+			reqCapture := common.NewBodyBuffer(cfg)
+			body := common.CountBody(req.Body, reqCapture)
+			if body != nil {
+				req.Body = body
+			}
+			tracker := &responseWriter{capture: common.NewBodyBuffer(cfg)}
 			ww := httpsnoop.Wrap(w, httpsnoop.Hooks{
 				WriteHeader: tracker.writeHeaderHook,
 				Write:       tracker.writeHook,
@@ -38,13 +66,20 @@ func Middleware(cfg Config) func(http.Handler) http.Handler {
 				recovered := recover()
 				status := common.ResolveStatus(tracker.statusCode, nil, recovered, tracker.wroteHeader, 0)
 				common.FinalizeRequest(cfg, common.FinalizeInput{
-					Ctx:        ctx,
-					Event:      event,
-					Method:     req.Method,
-					Path:       req.URL.Path,
-					Route:      req.Pattern,
-					StatusCode: status,
-					Recovered:  recovered,
+					Ctx:            ctx,
+					Event:          event,
+					Method:         req.Method,
+					Path:           req.URL.Path,
+					Route:          route(req),
+					StatusCode:     status,
+					Recovered:      recovered,
+					Header:         req.Header.Get,
+					ResponseHeader: w.Header().Get,
+
+					RequestBodySize:  body.Size(),
+					ResponseBodySize: tracker.bytesWritten,
+					RequestBody:      reqCapture,
+					ResponseBody:     tracker.capture,
 				})
 
 				if recovered != nil {
@@ -57,9 +92,15 @@ func Middleware(cfg Config) func(http.Handler) http.Handler {
 	}
 }
 
+func patternRoute(r *http.Request) string {
+	return r.Pattern
+}
+
 type responseWriter struct {
-	statusCode  int
-	wroteHeader bool
+	statusCode   int
+	wroteHeader  bool
+	bytesWritten int64
+	capture      *common.BodyBuffer
 }
 
 func (rw *responseWriter) writeHeaderHook(next httpsnoop.WriteHeaderFunc) httpsnoop.WriteHeaderFunc {
@@ -78,7 +119,10 @@ func (rw *responseWriter) writeHook(next httpsnoop.WriteFunc) httpsnoop.WriteFun
 			rw.statusCode = http.StatusOK
 			rw.wroteHeader = true
 		}
-		return next(p)
+		n, err := next(p)
+		rw.bytesWritten += int64(n)
+		_, _ = rw.capture.Write(p[:n])
+		return n, err
 	}
 }
 
@@ -88,6 +132,13 @@ func (rw *responseWriter) readFromHook(next httpsnoop.ReadFromFunc) httpsnoop.Re
 			rw.statusCode = http.StatusOK
 			rw.wroteHeader = true
 		}
-		return next(src)
+		if !rw.capture.Full() {
+			// Teeing disables sendfile for this response, so only do it
+			// while there is still room to capture.
+			src = io.TeeReader(src, rw.capture)
+		}
+		n, err := next(src)
+		rw.bytesWritten += n
+		return n, err
 	}
 }
@@ -116,6 +116,38 @@ func TestMiddlewarePanicPropagatesAndLogsError(t *testing.T) {
 	}
 }
 
+func TestMiddlewarePanicRecordsStack(t *testing.T) {
+	sink := &memorySink{}
+	mw := Middleware(Config{
+		Sink:         sink,
+		SamplingRate: 1,
+		PanicStack:   hc.PanicStackConfig{Enabled: true},
+	})
+
+	h := mw(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("bad")
+	}))
+
+	func() {
+		defer func() { _ = recover() }()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+	}()
+
+	events := sink.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	info := events[0].Fields["panic"].(map[string]any)
+	stack, ok := info["stack"].([]any)
+	if !ok || len(stack) == 0 {
+		t.Fatalf("expected panic stack, got %#v", info["stack"])
+	}
+	top := stack[0].(map[string]any)["function"].(string)
+	if !strings.Contains(top, "TestMiddlewarePanicRecordsStack") {
+		t.Fatalf("expected handler as top frame, got %q", top)
+	}
+}
+
 func TestMiddlewareWriteHeaderTwiceLogsFirstCommittedStatus(t *testing.T) {
 	backend := &memorySink{}
 	mw := Middleware(Config{
@@ -317,6 +349,195 @@ func TestMiddlewareReadFromSetsStatusCode(t *testing.T) {
 	if events[0].Fields["http.status"] != http.StatusOK {
 		t.Fatalf("expected status 200, got %v", events[0].Fields["http.status"])
 	}
+	if events[0].Fields["http.response.body_size"] != int64(2) {
+		t.Fatalf("expected response body size 2, got %v", events[0].Fields["http.response.body_size"])
+	}
+}
+
+func TestMiddlewareRecordsBodySizes(t *testing.T) {
+	sink := &memorySink{}
+	mw := Middleware(Config{
+		Sink:         sink,
+		SamplingRate: 1,
+	})
+
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := make([]byte, 4)
+		_, _ = io.ReadFull(r.Body, buf)
+		_, _ = w.Write([]byte("hello"))
+		_, _ = w.Write([]byte(" world"))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("partially-read body"))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	fields := sink.Events()[0].Fields
+	if fields["http.request.body_size"] != int64(4) {
+		t.Fatalf("request body size = %v, want bytes actually read", fields["http.request.body_size"])
+	}
+	if fields["http.response.body_size"] != int64(11) {
+		t.Fatalf("response body size = %v, want 11", fields["http.response.body_size"])
+	}
+}
+
+func TestMiddlewareCapturesBodiesOnError(t *testing.T) {
+	sink := &memorySink{}
+	mw := Middleware(Config{
+		Sink:         sink,
+		SamplingRate: 1,
+		BodyCapture:  hc.BodyCaptureConfig{Enabled: true, MaxBytes: 8},
+	})
+
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.(io.ReaderFrom).ReadFrom(strings.NewReader("upstream down"))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/proxy", strings.NewReader(`{"id":7}`))
+	req.Header.Set("Content-Type", "application/json")
+	h.ServeHTTP(&fullOptionalWriter{testOptionalWriter: testOptionalWriter{header: make(http.Header)}}, req)
+
+	fields := sink.Events()[0].Fields
+	if body, ok := fields["http.request.body"].(map[string]any); !ok || body["id"] != float64(7) {
+		t.Fatalf("request body = %#v", fields["http.request.body"])
+	}
+	if fields["http.response.body"] != "upstream" || fields["http.response.body_truncated"] != true {
+		t.Fatalf("response body = %v truncated=%v", fields["http.response.body"], fields["http.response.body_truncated"])
+	}
+}
+
+func TestMiddlewareHashSamplerUsesRequestHeader(t *testing.T) {
+	sink := &memorySink{}
+	mw := Middleware(Config{
+		Sink:    sink,
+		Sampler: hc.HashSampler(0.5, hc.KeyFromTraceID()),
+	})
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	sampler := hc.HashSampler(0.5, hc.KeyFromTraceID())
+	traceparents := []string{
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-a3ce929d0e0e47364bf92f3577b34da6-00f067aa0ba902b7-01",
+		"00-8448eb211c80319c0af7651916cd43dd-b7ad6b7169203331-01",
+	}
+	want := 0
+	for _, tp := range traceparents {
+		req := httptest.NewRequest(http.MethodGet, "/x", nil)
+		req.Header.Set("traceparent", tp)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if sampler(hc.SampleInput{Header: req.Header.Get}) {
+			want++
+		}
+	}
+
+	if got := len(sink.Events()); got != want {
+		t.Fatalf("expected %d sampled events, got %d", want, got)
+	}
+}
+
+func TestMiddlewareGeneratesAndEchoesRequestID(t *testing.T) {
+	sink := &memorySink{}
+	mw := Middleware(Config{
+		Sink:         sink,
+		SamplingRate: 1,
+		RequestID:    hc.RequestIDConfig{Enabled: true},
+	})
+	var seen string
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = hc.RequestID(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/x", nil))
+
+	if seen == "" {
+		t.Fatal("expected generated request ID in handler context")
+	}
+	if got := rr.Header().Get("X-Request-ID"); got != seen {
+		t.Fatalf("response header = %q, want %q", got, seen)
+	}
+	if got := sink.Events()[0].Fields["request_id"]; got != seen {
+		t.Fatalf("request_id = %v, want %q", got, seen)
+	}
+}
+
+func TestMiddlewareSkipsExcludedPaths(t *testing.T) {
+	sink := &memorySink{}
+	mw := Middleware(Config{
+		Sink:         sink,
+		SamplingRate: 1,
+		Exclude:      hc.ExcludeConfig{Paths: []string{"/healthz"}},
+	})
+	hasEvent := false
+	h := mw(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		hasEvent = hc.FromContext(r.Context()) != nil
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rr := httptest.NewRecorder()
+	allocs := testing.AllocsPerRun(100, func() { h.ServeHTTP(rr, req) })
+
+	if hasEvent || len(sink.Events()) != 0 {
+		t.Fatalf("expected excluded request to skip the event, hasEvent=%v events=%d", hasEvent, len(sink.Events()))
+	}
+	if allocs != 0 {
+		t.Fatalf("excluded request allocated %.0f times, want 0", allocs)
+	}
+}
+
+func TestMiddlewareDiscardsExcludedRoutes(t *testing.T) {
+	sink := &memorySink{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics/{kind}", func(http.ResponseWriter, *http.Request) {})
+	mux.HandleFunc("GET /orders/{id}", func(http.ResponseWriter, *http.Request) {})
+	h := Middleware(Config{
+		Sink:         sink,
+		SamplingRate: 1,
+		Exclude:      hc.ExcludeConfig{Routes: []string{"GET /metrics/{kind}"}},
+	})(mux)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics/cpu", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+
+	events := sink.Events()
+	if len(events) != 1 || events[0].Fields["http.route"] != "GET /orders/{id}" {
+		t.Fatalf("expected only the orders event, got %+v", events)
+	}
+}
+
+func TestMiddlewareLogsPanicOnExcludedRoute(t *testing.T) {
+	sink := &memorySink{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics/{kind}", func(http.ResponseWriter, *http.Request) {
+		panic("scrape failed")
+	})
+	h := Middleware(Config{
+		Sink:         sink,
+		SamplingRate: 1,
+		Exclude:      hc.ExcludeConfig{Routes: []string{"GET /metrics/{kind}"}},
+	})(mux)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic to propagate")
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics/cpu", nil))
+	}()
+
+	events := sink.Events()
+	if len(events) != 1 || events[0].Level != hc.LevelError {
+		t.Fatalf("expected the panicking excluded route to log at ERROR, got %+v", events)
+	}
+	if _, ok := events[0].Fields["panic"]; !ok {
+		t.Fatalf("expected panic field, got %v", events[0].Fields)
+	}
 }
 
 func TestMiddlewareNilSinkStillRunsHandler(t *testing.T) {
@@ -8,3 +8,18 @@ func isValidLevel(level Level) bool {
 		return false
 	}
 }
+
+func levelRank(level Level) int {
+	switch level {
+	case LevelDebug:
+		return 10
+	case LevelInfo:
+		return 20
+	case LevelWarn:
+		return 30
+	case LevelError:
+		return 40
+	default:
+		return 20
+	}
+}
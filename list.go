@@ -0,0 +1,152 @@
+package hc
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultAppendLimit is the number of values Append keeps per key.
+	DefaultAppendLimit = 32
+
+	// DefaultBreadcrumbLimit is the number of most recent breadcrumbs kept
+	// per event when Config.BreadcrumbLimit is not set.
+	DefaultBreadcrumbLimit = 20
+
+	breadcrumbField = "breadcrumbs"
+	droppedSuffix   = "_dropped"
+)
+
+// Append adds values to the list field key on the event stored in ctx.
+//
+// The list keeps the first DefaultAppendLimit values; further values are not
+// recorded and are counted in the <key>_dropped field instead.
+// Append(ctx, "flags.evaluated", "new_checkout", "beta_search").
+func Append(ctx context.Context, key string, values ...any) bool {
+	return AppendCapped(ctx, key, DefaultAppendLimit, values...)
+}
+
+// AppendCapped is like Append but keeps at most limit values under key.
+// A limit below 1 drops every value.
+func AppendCapped(ctx context.Context, key string, limit int, values ...any) bool {
+	e := FromContext(ctx)
+	if e == nil {
+		return false
+	}
+	if len(values) > 0 {
+		e.appendValues(key, limit, values)
+	}
+	return true
+}
+
+// Breadcrumb records a timestamped entry in the breadcrumbs field of the
+// event stored in ctx, with optional key/value pairs:
+// Breadcrumb(ctx, "cart loaded", "items", 3).
+//
+// Only the last Config.BreadcrumbLimit entries, DefaultBreadcrumbLimit
+// unless set, are kept; older entries are counted in breadcrumbs_dropped. kv must have even length and every key position
+// must be a string. Each entry holds time and message, which kv cannot
+// override.
+func Breadcrumb(ctx context.Context, msg string, kv ...any) bool {
+	e := FromContext(ctx)
+	if e == nil {
+		return false
+	}
+	if len(kv)%2 != 0 {
+		return false
+	}
+	entry := make(map[string]any, 2+len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		k, ok := kv[i].(string)
+		if !ok {
+			return false
+		}
+		entry[k] = kv[i+1]
+	}
+	entry["time"] = time.Now()
+	entry["message"] = msg
+	e.addBreadcrumb(entry)
+	return true
+}
+
+func (e *Event) appendValues(key string, limit int, values []any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	l := aggregateLocked(e, key, newValueList)
+	if l.append(values, limit) {
+		e.putLocked(Int(key+droppedSuffix, l.dropped))
+	}
+}
+
+func (e *Event) addBreadcrumb(entry map[string]any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b := aggregateLocked(e, breadcrumbField, e.newBreadcrumbs)
+	if b.add(entry) {
+		e.putLocked(Int(breadcrumbField+droppedSuffix, b.dropped))
+	}
+}
+
+// valueList keeps the first values appended under one field.
+type valueList struct {
+	values  []any
+	dropped int
+}
+
+func newValueList() *valueList {
+	return &valueList{}
+}
+
+// append records values up to limit and reports whether any were dropped.
+func (l *valueList) append(values []any, limit int) bool {
+	room := max(limit-len(l.values), 0)
+	if len(values) <= room {
+		l.values = append(l.values, values...)
+		return false
+	}
+	l.values = append(l.values, values[:room]...)
+	l.dropped += len(values) - room
+	return true
+}
+
+func (l *valueList) fieldValue() any {
+	out := make([]any, len(l.values))
+	copy(out, l.values)
+	return out
+}
+
+// breadcrumbs is a ring buffer of the most recent breadcrumb entries.
+type breadcrumbs struct {
+	entries []any
+	limit   int
+	next    int
+	dropped int
+}
+
+func (e *Event) newBreadcrumbs() *breadcrumbs {
+	limit := e.breadcrumbLimit
+	if limit <= 0 {
+		limit = DefaultBreadcrumbLimit
+	}
+	return &breadcrumbs{entries: make([]any, 0, limit), limit: limit}
+}
+
+// add records entry and reports whether an older entry was evicted.
+func (b *breadcrumbs) add(entry map[string]any) bool {
+	if len(b.entries) < b.limit {
+		b.entries = append(b.entries, entry)
+		return false
+	}
+	b.entries[b.next] = entry
+	b.next = (b.next + 1) % b.limit
+	b.dropped++
+	return true
+}
+
+func (b *breadcrumbs) fieldValue() any {
+	// Entries are never mutated after being recorded, so only the
+	// container is copied, oldest entry first.
+	out := make([]any, 0, len(b.entries))
+	out = append(out, b.entries[b.next:]...)
+	return append(out, b.entries[:b.next]...)
+}
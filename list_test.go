@@ -0,0 +1,138 @@
+package hc
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAppendAccumulatesValues(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	Append(ctx, "steps", "load_cart")
+	Append(ctx, "steps", "price", "charge")
+
+	fields := EventFields(e)
+	steps, ok := fields["steps"].([]any)
+	if !ok || len(steps) != 3 || steps[0] != "load_cart" || steps[2] != "charge" {
+		t.Fatalf("unexpected steps: %#v", fields["steps"])
+	}
+	if _, ok := fields["steps_dropped"]; ok {
+		t.Fatal("expected no dropped count without overflow")
+	}
+}
+
+func TestAppendCappedCountsOverflow(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	AppendCapped(ctx, "flags", 2, "a")
+	AppendCapped(ctx, "flags", 2, "b", "c", "d")
+
+	fields := EventFields(e)
+	flags := fields["flags"].([]any)
+	if len(flags) != 2 || flags[1] != "b" {
+		t.Fatalf("unexpected flags: %#v", flags)
+	}
+	if fields["flags_dropped"] != 2 {
+		t.Fatalf("flags_dropped = %#v, want 2", fields["flags_dropped"])
+	}
+}
+
+func TestAppendSnapshotIsStable(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	Append(ctx, "steps", "a")
+	before := EventFields(e)["steps"].([]any)
+	Append(ctx, "steps", "b")
+
+	if len(before) != 1 {
+		t.Fatalf("snapshot changed after later append: %#v", before)
+	}
+}
+
+func TestAppendConcurrent(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				Append(ctx, "steps", j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	fields := EventFields(e)
+	if got := len(fields["steps"].([]any)); got != DefaultAppendLimit {
+		t.Fatalf("expected %d steps, got %d", DefaultAppendLimit, got)
+	}
+	if fields["steps_dropped"] != 80-DefaultAppendLimit {
+		t.Fatalf("steps_dropped = %#v, want %d", fields["steps_dropped"], 80-DefaultAppendLimit)
+	}
+}
+
+func TestBreadcrumbKeepsLastEntries(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	before := time.Now()
+	for i := 0; i < DefaultBreadcrumbLimit+5; i++ {
+		if !Breadcrumb(ctx, "step "+strconv.Itoa(i), "i", i) {
+			t.Fatal("expected breadcrumb to be recorded")
+		}
+	}
+
+	fields := EventFields(e)
+	crumbs := fields["breadcrumbs"].([]any)
+	if len(crumbs) != DefaultBreadcrumbLimit {
+		t.Fatalf("expected %d breadcrumbs, got %d", DefaultBreadcrumbLimit, len(crumbs))
+	}
+	first := crumbs[0].(map[string]any)
+	last := crumbs[len(crumbs)-1].(map[string]any)
+	if first["message"] != "step 5" || first["i"] != 5 {
+		t.Fatalf("unexpected oldest breadcrumb: %#v", first)
+	}
+	if last["message"] != "step 24" {
+		t.Fatalf("unexpected newest breadcrumb: %#v", last)
+	}
+	if ts, ok := first["time"].(time.Time); !ok || ts.Before(before) {
+		t.Fatalf("expected breadcrumb timestamp, got %#v", first["time"])
+	}
+	if fields["breadcrumbs_dropped"] != 5 {
+		t.Fatalf("breadcrumbs_dropped = %#v, want 5", fields["breadcrumbs_dropped"])
+	}
+}
+
+func TestBreadcrumbUsesConfiguredLimit(t *testing.T) {
+	ctx, e := NewContextWithConfig(context.Background(), Config{BreadcrumbLimit: 3})
+	for i := 0; i < 5; i++ {
+		Breadcrumb(ctx, "step "+strconv.Itoa(i))
+	}
+
+	fields := EventFields(e)
+	crumbs := fields["breadcrumbs"].([]any)
+	if len(crumbs) != 3 || crumbs[0].(map[string]any)["message"] != "step 2" {
+		t.Fatalf("breadcrumbs = %#v", crumbs)
+	}
+	if fields["breadcrumbs_dropped"] != 2 {
+		t.Fatalf("breadcrumbs_dropped = %#v, want 2", fields["breadcrumbs_dropped"])
+	}
+}
+
+func TestBreadcrumbRejectsInvalidKV(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	if Breadcrumb(ctx, "x", "odd") || Breadcrumb(ctx, "x", 1, 2) {
+		t.Fatal("expected invalid kv to be rejected")
+	}
+	Breadcrumb(ctx, "y", "message", "override")
+	crumb := EventFields(e)["breadcrumbs"].([]any)[0].(map[string]any)
+	if crumb["message"] != "y" {
+		t.Fatalf("expected message to win over kv, got %#v", crumb)
+	}
+}
+
+func TestListHelpersNoopWithoutEvent(t *testing.T) {
+	ctx := context.Background()
+	if Append(ctx, "a", 1) || Breadcrumb(ctx, "a") {
+		t.Fatal("expected list helpers without event to report false")
+	}
+}
@@ -1,5 +1,7 @@
 package hc
 
+import "net/netip"
+
 const defaultMessage = "request_completed"
 
 // Config controls request finalization behavior.
@@ -21,4 +23,153 @@ type Config struct {
 
 	// Message is the final log message.
 	Message string
+
+	// ErrorLevels overrides the level of events by the class of their most
+	// severe error. Defaults are ERROR for ErrorClassServer and
+	// ErrorClassTransient, WARN for ErrorClassClient, and INFO for
+	// ErrorClassCanceled. Overrides only change the level: server and
+	// transient errors bypass sampling whatever their level. Responses with
+	// status 500 or above always log at ERROR.
+	ErrorLevels map[ErrorClass]Level
+
+	// Headers controls request and response header capture.
+	Headers HeaderConfig
+
+	// BodyCapture controls request and response body capture.
+	BodyCapture BodyCaptureConfig
+
+	// PanicStack controls stack capture for recovered panics.
+	PanicStack PanicStackConfig
+
+	// ErrorEncoder optionally adds fields extracted from each recorded
+	// error, such as a domain error code, to its error entry.
+	ErrorEncoder ErrorEncoder
+
+	// CaptureErrorStacks records the stack of each Error call site as the
+	// stack entry of the error field. Default is false.
+	CaptureErrorStacks bool
+
+	// BreadcrumbLimit is the number of most recent breadcrumbs kept per
+	// event. Default is DefaultBreadcrumbLimit (20).
+	BreadcrumbLimit int
+
+	// TrustedProxies lists proxy networks whose forwarding headers
+	// (Forwarded, X-Forwarded-For, X-Real-IP) are honored when resolving
+	// client.address. When empty, the connection peer address is recorded.
+	TrustedProxies []netip.Prefix
+
+	// Redactor scrubs sensitive fields before they reach Sink and finalizers.
+	Redactor *Redactor
+
+	// RequestID controls request ID extraction, generation, and echoing.
+	RequestID RequestIDConfig
+
+	// Exclude lists requests that bypass event capture entirely.
+	Exclude ExcludeConfig
+}
+
+// ExcludeConfig lists requests that bypass event capture.
+//
+// Requests matching Paths, PathPrefixes, or PathGlobs never allocate an
+// event; the handler runs with no event in its context. gRPC interceptors
+// match these against the full method, such as
+// "/grpc.health.v1.Health/Check".
+type ExcludeConfig struct {
+	// Paths lists exact request paths, such as "/healthz".
+	Paths []string
+
+	// PathPrefixes lists request path prefixes, such as "/debug/pprof/".
+	PathPrefixes []string
+
+	// PathGlobs lists path.Match patterns, such as "/static/*/*.js".
+	// Malformed patterns never match.
+	PathGlobs []string
+
+	// Routes lists route templates, such as "/metrics" or "/orders/:id",
+	// compared exactly with the event's http.route. Unlike paths, routes are
+	// known only once the handler returns, so matching events are created
+	// and discarded at finalization without running finalizers or reaching
+	// Sink. Requests that panic, respond with status 500 or above, or record
+	// a server or transient error are still logged.
+	Routes []string
+}
+
+// PanicStackConfig controls stack capture for panics recovered by
+// integrations.
+//
+// The stack of the panicking goroutine is recorded as panic.stack, a list of
+// frames holding function, file, and line, starting at the panic site.
+type PanicStackConfig struct {
+	// Enabled turns on stack capture.
+	Enabled bool
+
+	// MaxFrames bounds the recorded frames. Default is 32.
+	MaxFrames int
+
+	// TrimPrefixes lists prefixes, such as a module path or build directory,
+	// removed from frame functions and files.
+	TrimPrefixes []string
+
+	// SkipStdlib drops frames from the runtime and standard library.
+	SkipStdlib bool
+}
+
+// RequestIDConfig controls request ID handling.
+type RequestIDConfig struct {
+	// Enabled records a request ID on every event as request_id and echoes
+	// it in the response.
+	Enabled bool
+
+	// Headers are checked in order for an incoming request ID.
+	// Default is X-Request-ID.
+	Headers []string
+
+	// ResponseHeader carries the request ID on the response.
+	// Default is the first entry of Headers.
+	ResponseHeader string
+
+	// Generator creates IDs for requests without a usable incoming ID.
+	// Default is NewRequestID.
+	Generator func() string
+}
+
+// HeaderConfig lists headers recorded on the event.
+//
+// Request headers are recorded as http.request.header.<name> and response
+// headers as http.response.header.<name>, with lowercase names. Absent
+// headers are not recorded.
+type HeaderConfig struct {
+	// Request lists request headers to record.
+	Request []string
+
+	// Response lists response headers to record.
+	Response []string
+
+	// Sensitive lists headers whose values are recorded as "[REDACTED]", in
+	// addition to Authorization, Cookie, Set-Cookie, and Proxy-Authorization.
+	Sensitive []string
+
+	// SkipDefaultSensitive drops the default sensitive headers, so only
+	// headers in Sensitive are redacted.
+	SkipDefaultSensitive bool
+}
+
+// BodyCaptureConfig controls bounded body capture.
+//
+// Captured bodies are attached as http.request.body and http.response.body
+// only when the event has an error or was kept with KeepEvent. Complete JSON
+// bodies are attached as parsed values so Config.Redactor rules apply to
+// their fields; other bodies are attached as strings. Truncated bodies also
+// set http.request.body_truncated or http.response.body_truncated.
+type BodyCaptureConfig struct {
+	// Enabled turns on body capture.
+	Enabled bool
+
+	// MaxBytes bounds the bytes kept per body. Default is 4096.
+	MaxBytes int
+
+	// ContentTypes lists media types to attach, as path.Match patterns such
+	// as "text/*". Default is application/json, application/*+json,
+	// text/plain, and application/x-www-form-urlencoded.
+	ContentTypes []string
 }
@@ -0,0 +1,391 @@
+package hc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+const defaultRedactMask = "[REDACTED]"
+
+// RedactAction selects what a Redactor does with a matched value.
+type RedactAction uint8
+
+const (
+	// RedactMask replaces the matched value with RedactorOptions.Mask.
+	RedactMask RedactAction = iota
+	// RedactDrop removes the field, or the slice element, holding the match.
+	RedactDrop
+	// RedactHash replaces the matched value with a keyed HMAC-SHA256 digest,
+	// so equal values stay correlatable without being readable.
+	RedactHash
+)
+
+// KeyMatcher reports whether a field key should be redacted.
+//
+// Matchers are called with both the field name and its dotted path, e.g.
+// "email" and "user.email" for a nested field.
+type KeyMatcher func(key string) bool
+
+// ValueDetector returns the [start, end) byte ranges of sensitive data in s.
+type ValueDetector func(s string) [][]int
+
+// RedactRule pairs a key matcher and/or value detector with an action.
+//
+// A rule with only Key redacts whole values under matching keys. A rule with
+// only Value redacts detected substrings in every string value. A rule with
+// both redacts detected substrings under matching keys only.
+type RedactRule struct {
+	Key    KeyMatcher
+	Value  ValueDetector
+	Action RedactAction
+}
+
+// RedactorOptions controls Redactor behavior.
+type RedactorOptions struct {
+	// Rules are checked in order. The first key-only rule matching a field
+	// decides its value; value detectors all apply.
+	Rules []RedactRule
+
+	// HashKey keys RedactHash digests. Without a key, RedactHash masks instead.
+	HashKey []byte
+
+	// Mask replaces masked values. Default is "[REDACTED]".
+	Mask string
+}
+
+// Redactor scrubs sensitive fields before they reach a sink.
+//
+// Nested map[string]any, []any, and []string values are walked. Values
+// shared with the event are copied before modification, never mutated.
+type Redactor struct {
+	keyRules   []RedactRule
+	valueRules []RedactRule
+	hashKey    []byte
+	mask       string
+}
+
+// NewRedactor builds a Redactor from opts.
+// Rules with neither Key nor Value are ignored.
+func NewRedactor(opts RedactorOptions) *Redactor {
+	r := &Redactor{
+		hashKey: append([]byte(nil), opts.HashKey...),
+		mask:    opts.Mask,
+	}
+	if r.mask == "" {
+		r.mask = defaultRedactMask
+	}
+	for _, rule := range opts.Rules {
+		switch {
+		case rule.Key != nil:
+			r.keyRules = append(r.keyRules, rule)
+		case rule.Value != nil:
+			r.valueRules = append(r.valueRules, rule)
+		}
+	}
+	return r
+}
+
+// Redact returns fields with sensitive values redacted.
+// fields is returned unchanged when nothing matches; otherwise a new map is
+// returned and fields is left untouched.
+func (r *Redactor) Redact(fields map[string]any) map[string]any {
+	if r == nil || len(r.keyRules)+len(r.valueRules) == 0 || len(fields) == 0 {
+		return fields
+	}
+	out, _ := r.redactMap("", fields, nil)
+	return out
+}
+
+// MatchKey returns a KeyMatcher for exact key names, ignoring case.
+func MatchKey(names ...string) KeyMatcher {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[strings.ToLower(name)] = struct{}{}
+	}
+	return func(key string) bool {
+		_, ok := set[strings.ToLower(key)]
+		return ok
+	}
+}
+
+// MatchKeyGlob returns a KeyMatcher for path.Match patterns, ignoring case.
+// Malformed patterns never match.
+func MatchKeyGlob(patterns ...string) KeyMatcher {
+	lowered := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		lowered = append(lowered, strings.ToLower(p))
+	}
+	return func(key string) bool {
+		key = strings.ToLower(key)
+		for _, p := range lowered {
+			if ok, _ := path.Match(p, key); ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchKeyRegexp returns a KeyMatcher using re.
+func MatchKeyRegexp(re *regexp.Regexp) KeyMatcher {
+	return func(key string) bool {
+		return re != nil && re.MatchString(key)
+	}
+}
+
+var (
+	emailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	cardPattern       = regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`)
+	jwtPattern        = regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]*\.eyJ[A-Za-z0-9_\-]*\.[A-Za-z0-9_\-]*`)
+	bearerPattern     = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`)
+	cardSeparatorTrim = strings.NewReplacer(" ", "", "-", "")
+)
+
+// DetectEmail returns a ValueDetector for email addresses.
+func DetectEmail() ValueDetector {
+	return func(s string) [][]int { return emailPattern.FindAllStringIndex(s, -1) }
+}
+
+// DetectCreditCard returns a ValueDetector for 13-19 digit card numbers,
+// optionally separated by spaces or dashes, that pass the Luhn check.
+func DetectCreditCard() ValueDetector {
+	return func(s string) [][]int {
+		matches := cardPattern.FindAllStringIndex(s, -1)
+		out := matches[:0]
+		for _, m := range matches {
+			if luhnValid(cardSeparatorTrim.Replace(s[m[0]:m[1]])) {
+				out = append(out, m)
+			}
+		}
+		return out
+	}
+}
+
+// DetectJWT returns a ValueDetector for JSON Web Tokens.
+func DetectJWT() ValueDetector {
+	return func(s string) [][]int { return jwtPattern.FindAllStringIndex(s, -1) }
+}
+
+// DetectBearerToken returns a ValueDetector for "Bearer <token>" credentials.
+func DetectBearerToken() ValueDetector {
+	return func(s string) [][]int { return bearerPattern.FindAllStringIndex(s, -1) }
+}
+
+func luhnValid(digits string) bool {
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// redactMap returns m with rules applied, copying m on first change.
+// scoped holds value rules activated by a matching ancestor key.
+func (r *Redactor) redactMap(prefix string, m map[string]any, scoped []RedactRule) (map[string]any, bool) {
+	var out map[string]any
+	for key, value := range m {
+		fieldPath := key
+		if prefix != "" {
+			fieldPath = prefix + "." + key
+		}
+		next, changed, drop := r.redactField(key, fieldPath, value, scoped)
+		if !changed && !drop {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]any, len(m))
+			for k, v := range m {
+				out[k] = v
+			}
+		}
+		if drop {
+			delete(out, key)
+			continue
+		}
+		out[key] = next
+	}
+	if out == nil {
+		return m, false
+	}
+	return out, true
+}
+
+// redactField applies key rules to one field, then walks its value.
+func (r *Redactor) redactField(key, fieldPath string, value any, scoped []RedactRule) (any, bool, bool) {
+	for _, rule := range r.keyRules {
+		if !rule.Key(key) && (fieldPath == key || !rule.Key(fieldPath)) {
+			continue
+		}
+		if rule.Value != nil {
+			scoped = append(scoped[:len(scoped):len(scoped)], rule)
+			continue
+		}
+		switch rule.Action {
+		case RedactDrop:
+			return nil, false, true
+		case RedactHash:
+			return r.hash(fmt.Sprint(value)), true, false
+		default:
+			return r.mask, true, false
+		}
+	}
+	return r.redactValue(fieldPath, value, scoped)
+}
+
+// redactValue walks value, returning the redacted value, whether it
+// changed, and whether it should be dropped.
+func (r *Redactor) redactValue(fieldPath string, value any, scoped []RedactRule) (any, bool, bool) {
+	switch v := value.(type) {
+	case string:
+		return r.redactString(v, scoped)
+	case map[string]any:
+		out, changed := r.redactMap(fieldPath, v, scoped)
+		return out, changed, false
+	case []any:
+		var out []any
+		for i, elem := range v {
+			next, changed, drop := r.redactValue(fieldPath, elem, scoped)
+			if !changed && !drop {
+				if out != nil {
+					out = append(out, elem)
+				}
+				continue
+			}
+			if out == nil {
+				out = append(make([]any, 0, len(v)), v[:i]...)
+			}
+			if !drop {
+				out = append(out, next)
+			}
+		}
+		if out == nil {
+			return v, false, false
+		}
+		return out, true, false
+	case []string:
+		var out []string
+		for i, elem := range v {
+			next, changed, drop := r.redactString(elem, scoped)
+			if !changed && !drop {
+				if out != nil {
+					out = append(out, elem)
+				}
+				continue
+			}
+			if out == nil {
+				out = append(make([]string, 0, len(v)), v[:i]...)
+			}
+			if !drop {
+				out = append(out, next.(string))
+			}
+		}
+		if out == nil {
+			return v, false, false
+		}
+		return out, true, false
+	default:
+		return r.redactReflect(fieldPath, value, scoped)
+	}
+}
+
+// redactReflect walks string-keyed maps and slices of other types, such as
+// map[string]string or []map[string]any. A changed value is returned as
+// map[string]any or []any.
+func (r *Redactor) redactReflect(fieldPath string, value any, scoped []RedactRule) (any, bool, bool) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String || rv.Len() == 0 {
+			return value, false, false
+		}
+		m := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		out, changed := r.redactMap(fieldPath, m, scoped)
+		if !changed {
+			return value, false, false
+		}
+		return out, true, false
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 || rv.Len() == 0 {
+			return value, false, false
+		}
+		elems := make([]any, rv.Len())
+		for i := range elems {
+			elems[i] = rv.Index(i).Interface()
+		}
+		out, changed, _ := r.redactValue(fieldPath, elems, scoped)
+		if !changed {
+			return value, false, false
+		}
+		return out, true, false
+	default:
+		return value, false, false
+	}
+}
+
+func (r *Redactor) redactString(s string, scoped []RedactRule) (any, bool, bool) {
+	changed := false
+	for _, rules := range [2][]RedactRule{r.valueRules, scoped} {
+		for _, rule := range rules {
+			matches := rule.Value(s)
+			if len(matches) == 0 {
+				continue
+			}
+			if rule.Action == RedactDrop {
+				return nil, false, true
+			}
+			s = r.replaceMatches(s, matches, rule.Action)
+			changed = true
+		}
+	}
+	return s, changed, false
+}
+
+func (r *Redactor) replaceMatches(s string, matches [][]int, action RedactAction) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if m[0] < last {
+			continue
+		}
+		b.WriteString(s[last:m[0]])
+		if action == RedactHash {
+			b.WriteString(r.hash(s[m[0]:m[1]]))
+		} else {
+			b.WriteString(r.mask)
+		}
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func (r *Redactor) hash(value string) string {
+	if len(r.hashKey) == 0 {
+		return r.mask
+	}
+	mac := hmac.New(sha256.New, r.hashKey)
+	mac.Write([]byte(value))
+	return "hmac:" + hex.EncodeToString(mac.Sum(nil)[:16])
+}
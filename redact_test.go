@@ -0,0 +1,171 @@
+package hc
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRedactorKeyRules(t *testing.T) {
+	r := NewRedactor(RedactorOptions{
+		HashKey: []byte("secret"),
+		Rules: []RedactRule{
+			{Key: MatchKey("password")},
+			{Key: MatchKeyGlob("*token*"), Action: RedactDrop},
+			{Key: MatchKeyRegexp(regexp.MustCompile(`^user\.email$`)), Action: RedactHash},
+		},
+	})
+
+	fields := map[string]any{
+		"Password":      "hunter2",
+		"session_token": "abc",
+		"user":          map[string]any{"email": "a@example.com", "id": 7},
+		"ok":            "visible",
+	}
+	got := r.Redact(fields)
+
+	if got["Password"] != defaultRedactMask {
+		t.Fatalf("password = %v", got["Password"])
+	}
+	if _, ok := got["session_token"]; ok {
+		t.Fatal("expected token field to be dropped")
+	}
+	user := got["user"].(map[string]any)
+	email, _ := user["email"].(string)
+	if !strings.HasPrefix(email, "hmac:") || user["id"] != 7 {
+		t.Fatalf("user = %v", user)
+	}
+	if got["ok"] != "visible" {
+		t.Fatalf("ok = %v", got["ok"])
+	}
+
+	again := r.Redact(map[string]any{"user": map[string]any{"email": "a@example.com"}})
+	if again["user"].(map[string]any)["email"] != email {
+		t.Fatal("expected keyed hash to be stable")
+	}
+}
+
+func TestRedactorValueDetectors(t *testing.T) {
+	r := NewRedactor(RedactorOptions{
+		Mask: "***",
+		Rules: []RedactRule{
+			{Value: DetectEmail()},
+			{Value: DetectCreditCard()},
+			{Value: DetectJWT()},
+			{Value: DetectBearerToken()},
+		},
+	})
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "email", in: "contact bob@example.com now", want: "contact *** now"},
+		{name: "card", in: "card 4111 1111 1111 1111 ok", want: "card *** ok"},
+		{name: "non luhn digits", in: "order 1234567890123", want: "order 1234567890123"},
+		{name: "jwt", in: "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.sig-_x", want: "***"},
+		{name: "bearer", in: "Authorization: Bearer abc.def-123", want: "Authorization: ***"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.Redact(map[string]any{"v": tt.in})
+			if got["v"] != tt.want {
+				t.Fatalf("v = %q, want %q", got["v"], tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactorScopedValueRule(t *testing.T) {
+	r := NewRedactor(RedactorOptions{Rules: []RedactRule{
+		{Key: MatchKey("headers"), Value: DetectBearerToken()},
+	}})
+
+	got := r.Redact(map[string]any{
+		"headers": map[string]any{"authorization": "Bearer xyz"},
+		"note":    "Bearer xyz",
+	})
+	if got["headers"].(map[string]any)["authorization"] != defaultRedactMask {
+		t.Fatalf("headers = %v", got["headers"])
+	}
+	if got["note"] != "Bearer xyz" {
+		t.Fatalf("note = %v, want untouched", got["note"])
+	}
+}
+
+func TestRedactorDoesNotMutateSharedValues(t *testing.T) {
+	r := NewRedactor(RedactorOptions{Rules: []RedactRule{
+		{Value: DetectEmail()},
+		{Value: DetectCreditCard(), Action: RedactDrop},
+	}})
+
+	nested := map[string]any{"email": "a@example.com", "plan": "pro"}
+	list := []any{"x@example.com", "4111111111111111", "keep"}
+	tags := []string{"y@example.com", "t"}
+	fields := map[string]any{"user": nested, "list": list, "tags": tags}
+
+	got := r.Redact(fields)
+
+	if nested["email"] != "a@example.com" || list[0] != "x@example.com" || tags[0] != "y@example.com" {
+		t.Fatal("shared nested values were mutated")
+	}
+	if fields["user"].(map[string]any)["email"] != "a@example.com" {
+		t.Fatal("input map was mutated")
+	}
+	gotList := got["list"].([]any)
+	if len(gotList) != 2 || gotList[0] != defaultRedactMask || gotList[1] != "keep" {
+		t.Fatalf("list = %v", gotList)
+	}
+	if got["tags"].([]string)[0] != defaultRedactMask {
+		t.Fatalf("tags = %v", got["tags"])
+	}
+}
+
+func TestRedactorWalksTypedMapsAndSlices(t *testing.T) {
+	r := NewRedactor(RedactorOptions{Rules: []RedactRule{
+		{Key: MatchKey("password")},
+		{Value: DetectEmail()},
+	}})
+
+	creds := map[string]string{"user": "bob", "password": "hunter2"}
+	items := []map[string]any{{"id": 1}, {"id": 2, "contact": "a@example.com"}}
+	got := r.Redact(map[string]any{"creds": creds, "items": items, "ids": []int{1, 2}})
+
+	gotCreds := got["creds"].(map[string]any)
+	if gotCreds["password"] != defaultRedactMask || gotCreds["user"] != "bob" {
+		t.Fatalf("creds = %v", gotCreds)
+	}
+	gotItems := got["items"].([]any)
+	if gotItems[1].(map[string]any)["contact"] != defaultRedactMask || gotItems[0].(map[string]any)["id"] != 1 {
+		t.Fatalf("items = %v", gotItems)
+	}
+	if creds["password"] != "hunter2" || items[1]["contact"] != "a@example.com" {
+		t.Fatal("typed input values were mutated")
+	}
+	if _, ok := got["ids"].([]int); !ok {
+		t.Fatalf("ids = %T, want unchanged []int", got["ids"])
+	}
+}
+
+func TestRedactorReturnsInputWhenUnchanged(t *testing.T) {
+	fields := map[string]any{"a": "b"}
+	got := NewRedactor(RedactorOptions{Rules: []RedactRule{{Value: DetectEmail()}}}).Redact(fields)
+	got["c"] = 1
+	if _, ok := fields["c"]; !ok {
+		t.Fatal("expected unchanged input map to be returned as-is")
+	}
+
+	var nilRedactor *Redactor
+	if nilRedactor.Redact(fields) == nil {
+		t.Fatal("nil redactor should pass fields through")
+	}
+}
+
+func TestRedactorHashWithoutKeyMasks(t *testing.T) {
+	r := NewRedactor(RedactorOptions{Rules: []RedactRule{{Key: MatchKey("ssn"), Action: RedactHash}}})
+	if got := r.Redact(map[string]any{"ssn": "123-45-6789"}); got["ssn"] != defaultRedactMask {
+		t.Fatalf("ssn = %v, want mask", got["ssn"])
+	}
+}
@@ -0,0 +1,62 @@
+package hc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// DefaultRequestIDHeader is the request ID header used when
+// RequestIDConfig.Headers is empty.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+const requestIDField = "request_id"
+
+// NewRequestID returns a random UUIDv7 string.
+// IDs generated in later milliseconds sort after earlier ones.
+func NewRequestID() string {
+	var b [16]byte
+	ms := uint64(time.Now().UnixMilli())
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+	_, _ = rand.Read(b[6:])
+	b[6] = 0x70 | b[6]&0x0f
+	b[8] = 0x80 | b[8]&0x3f
+
+	var out [36]byte
+	hex.Encode(out[0:8], b[0:4])
+	out[8] = '-'
+	hex.Encode(out[9:13], b[4:6])
+	out[13] = '-'
+	hex.Encode(out[14:18], b[6:8])
+	out[18] = '-'
+	hex.Encode(out[19:23], b[8:10])
+	out[23] = '-'
+	hex.Encode(out[24:], b[10:])
+	return string(out[:])
+}
+
+// SetRequestID records id as the request_id field on the event in ctx.
+func SetRequestID(ctx context.Context, id string) bool {
+	if id == "" {
+		return false
+	}
+	return Add(ctx, requestIDField, id)
+}
+
+// RequestID returns the request ID recorded on the event in ctx, or an
+// empty string if none is set.
+func RequestID(ctx context.Context) string {
+	e := FromContext(ctx)
+	if e == nil {
+		return ""
+	}
+	v, _ := e.lookup(requestIDField)
+	id, _ := v.(string)
+	return id
+}
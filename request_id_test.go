@@ -0,0 +1,56 @@
+package hc
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var uuidV7Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewRequestIDIsUUIDv7(t *testing.T) {
+	seen := make(map[string]bool, 100)
+	for range 100 {
+		id := NewRequestID()
+		if !uuidV7Pattern.MatchString(id) {
+			t.Fatalf("id %q is not a UUIDv7", id)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate id %q", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestNewRequestIDSortsByTime(t *testing.T) {
+	first := NewRequestID()
+	time.Sleep(2 * time.Millisecond)
+	second := NewRequestID()
+	if first >= second {
+		t.Fatalf("expected %q to sort before %q", first, second)
+	}
+}
+
+func TestSetRequestID(t *testing.T) {
+	if SetRequestID(context.Background(), "abc") {
+		t.Fatal("expected false without event")
+	}
+	if RequestID(context.Background()) != "" {
+		t.Fatal("expected empty request ID without event")
+	}
+
+	ctx, e := NewContext(context.Background())
+	if SetRequestID(ctx, "") {
+		t.Fatal("expected empty ID to be rejected")
+	}
+	if !SetRequestID(ctx, "req-1") {
+		t.Fatal("expected SetRequestID to succeed")
+	}
+	if got := RequestID(ctx); got != "req-1" {
+		t.Fatalf("RequestID = %q, want req-1", got)
+	}
+	if EventFields(e)["request_id"] != "req-1" {
+		t.Fatalf("request_id field = %v", EventFields(e)["request_id"])
+	}
+}
@@ -13,13 +13,80 @@ type SampleInput struct {
 	StatusCode int
 	Duration   time.Duration
 	Level      Level
-	HasError   bool
-	Event      *Event
+	// HasError reports a status of 500 or above, or an error of class
+	// ErrorClassServer or ErrorClassTransient, whatever its level under
+	// Config.ErrorLevels.
+	HasError bool
+	Event    *Event
+	// Header returns a request header (or RPC metadata) value, or "" when absent.
+	// It may be nil when the integration does not expose headers.
+	Header func(name string) string
+	// RequestSize and ResponseSize are body byte counts for HTTP requests.
+	RequestSize  int64
+	ResponseSize int64
+}
+
+// ForcedDecision is a sampling outcome forced on an event before finalization.
+type ForcedDecision uint8
+
+const (
+	// ForceNone leaves the decision to the configured sampling rules.
+	ForceNone ForcedDecision = iota
+	// ForceKeep writes the event regardless of sampling rules.
+	ForceKeep
+	// ForceDrop discards the event regardless of sampling rules, including errors.
+	ForceDrop
+)
+
+// String returns the field value recorded for d.
+func (d ForcedDecision) String() string {
+	switch d {
+	case ForceKeep:
+		return "keep"
+	case ForceDrop:
+		return "drop"
+	default:
+		return "none"
+	}
 }
 
 // Sampler returns true when an event should be written.
 type Sampler func(SampleInput) bool
 
+// SampleResult is a sampling decision together with the probability used to
+// reach it.
+type SampleResult struct {
+	Keep bool
+	// Rate is the probability in (0,1] that a comparable event is kept.
+	// Kept events represent 1/Rate events. Values outside (0,1] report 1.
+	Rate float64
+}
+
+// ResultSampler returns a sampling decision with its sample rate.
+type ResultSampler func(SampleInput) SampleResult
+
+// FromResultSampler adapts s to a Sampler.
+//
+// The rate of kept events is reported on the event so finalization can
+// attach it as the sample_rate field.
+func FromResultSampler(s ResultSampler) Sampler {
+	if s == nil {
+		return NeverSampler()
+	}
+	return func(in SampleInput) bool {
+		res := s(in)
+		if !res.Keep {
+			return false
+		}
+		rate := res.Rate
+		if rate <= 0 || rate > 1 {
+			rate = 1
+		}
+		reportSampleRate(in.Event, rate)
+		return true
+	}
+}
+
 // SamplerMiddleware wraps a sampler with additional decision logic.
 type SamplerMiddleware func(next Sampler) Sampler
 
@@ -80,6 +147,21 @@ func KeepSlowerThan(minDuration time.Duration) SamplerMiddleware {
 	}
 }
 
+// KeepLargerThan returns middleware that keeps requests whose request or
+// response body exceeds bytes.
+//
+// Negative sizes are treated as zero.
+func KeepLargerThan(bytes int64) SamplerMiddleware {
+	if bytes < 0 {
+		bytes = 0
+	}
+	return func(next Sampler) Sampler {
+		return func(in SampleInput) bool {
+			return in.RequestSize > bytes || in.ResponseSize > bytes || next(in)
+		}
+	}
+}
+
 // KeepPathPrefix returns middleware that keeps requests matching path prefixes.
 func KeepPathPrefix(prefixes ...string) SamplerMiddleware {
 	filtered := make([]string, 0, len(prefixes))
@@ -106,6 +188,7 @@ func KeepPathPrefix(prefixes ...string) SamplerMiddleware {
 // RateSampler returns a probabilistic sampler using rate in [0,1].
 //
 // Values <= 0 always drop. Values >= 1 always keep.
+// Kept events report rate as their sample rate.
 func RateSampler(rate float64) Sampler {
 	switch {
 	case rate <= 0:
@@ -114,11 +197,21 @@ func RateSampler(rate float64) Sampler {
 		return AlwaysSampler()
 	default:
 		return func(in SampleInput) bool {
-			return nextSampleFloat64() < rate
+			if nextSampleFloat64() >= rate {
+				return false
+			}
+			reportSampleRate(in.Event, rate)
+			return true
 		}
 	}
 }
 
+func reportSampleRate(e *Event, rate float64) {
+	if e != nil {
+		e.setSampleRate(rate)
+	}
+}
+
 func nextSampleFloat64() float64 {
 	x := samplerState.Add(0x9e3779b97f4a7c15)
 	x ^= x >> 12
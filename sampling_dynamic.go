@@ -0,0 +1,211 @@
+package hc
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultDynamicTarget  = 10
+	defaultDynamicWindow  = 30 * time.Second
+	defaultDynamicMaxKeys = 1000
+	dynamicOverflowKey    = "__overflow__"
+
+	// dynamicSteps is how many times per window rates are recomputed.
+	dynamicSteps = 10
+)
+
+// DynamicSamplerOptions controls DynamicSampler behavior.
+type DynamicSamplerOptions struct {
+	// TargetEventsPerSecond is the desired total output rate. Default is 10.
+	TargetEventsPerSecond float64
+
+	// Window is the span of recent traffic that per-key rates are computed
+	// from. Rates are recomputed ten times per window. Default is 30s.
+	Window time.Duration
+
+	// Key groups requests for rate allocation.
+	// Default is the http.route field (or Path) plus the status class, e.g. "/orders/{id} 2xx".
+	Key func(SampleInput) string
+
+	// MaxKeys bounds distinct keys tracked per window; further keys share one
+	// bucket. Default is 1000.
+	MaxKeys int
+}
+
+// DynamicSampler returns a sampler that adapts per-key rates so total output
+// stays near TargetEventsPerSecond.
+//
+// Traffic is counted per key over a sliding window, estimated from the
+// current and previous windows with the previous one weighted by how much of
+// it still overlaps. Rates split the event budget by the logarithm of each
+// key's volume: rare keys are kept at or near rate 1 while hot keys absorb
+// most of the reduction. Keys not yet counted are sampled at the overall
+// rate. The effective rate is reported as the sample rate of every kept
+// event.
+func DynamicSampler(opts DynamicSamplerOptions) Sampler {
+	return newDynamicSampler(opts, time.Now).sample
+}
+
+type dynamicSampler struct {
+	budget  float64
+	window  time.Duration
+	key     func(SampleInput) string
+	maxKeys int
+	now     func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	nextUpdate  time.Time
+	prev        map[string]int
+	counts      map[string]int
+	rates       map[string]float64
+	newKeyRate  float64
+}
+
+func newDynamicSampler(opts DynamicSamplerOptions, now func() time.Time) *dynamicSampler {
+	if opts.TargetEventsPerSecond <= 0 {
+		opts.TargetEventsPerSecond = defaultDynamicTarget
+	}
+	if opts.Window <= 0 {
+		opts.Window = defaultDynamicWindow
+	}
+	if opts.Key == nil {
+		opts.Key = defaultDynamicKey
+	}
+	if opts.MaxKeys <= 0 {
+		opts.MaxKeys = defaultDynamicMaxKeys
+	}
+	start := now()
+	return &dynamicSampler{
+		budget:      opts.TargetEventsPerSecond * opts.Window.Seconds(),
+		window:      opts.Window,
+		key:         opts.Key,
+		maxKeys:     opts.MaxKeys,
+		now:         now,
+		windowStart: start,
+		nextUpdate:  start.Add(opts.Window / dynamicSteps),
+		counts:      make(map[string]int),
+		newKeyRate:  1,
+	}
+}
+
+func (d *dynamicSampler) sample(in SampleInput) bool {
+	rate := d.rateFor(d.key(in))
+	if rate < 1 && nextSampleFloat64() >= rate {
+		return false
+	}
+	reportSampleRate(in.Event, rate)
+	return true
+}
+
+func (d *dynamicSampler) rateFor(key string) float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.advance(d.now())
+
+	if _, ok := d.counts[key]; !ok && len(d.counts) >= d.maxKeys {
+		key = dynamicOverflowKey
+	}
+	d.counts[key]++
+
+	if rate, ok := d.rates[key]; ok {
+		return rate
+	}
+	return d.newKeyRate
+}
+
+// advance rolls the window over at its boundaries, dropping counts older
+// than the previous window, and recomputes rates every window/dynamicSteps.
+// d.mu must be held.
+func (d *dynamicSampler) advance(now time.Time) {
+	if elapsed := now.Sub(d.windowStart); elapsed >= d.window {
+		windows := elapsed / d.window
+		if windows == 1 {
+			d.prev = d.counts
+		} else {
+			d.prev = nil
+		}
+		d.counts = make(map[string]int, len(d.counts))
+		d.windowStart = d.windowStart.Add(windows * d.window)
+	} else if now.Before(d.nextUpdate) {
+		return
+	}
+	d.nextUpdate = now.Add(d.window / dynamicSteps)
+
+	// The part of the previous window still inside the sliding window.
+	overlap := 1 - float64(now.Sub(d.windowStart))/float64(d.window)
+	volume := make(map[string]float64, len(d.prev)+len(d.counts))
+	for k, c := range d.prev {
+		volume[k] = float64(c) * overlap
+	}
+	for k, c := range d.counts {
+		volume[k] += float64(c)
+	}
+	d.rates, d.newKeyRate = allocateRates(volume, d.budget)
+}
+
+// allocateRates splits budget across keys by log10 volume, visiting keys from
+// rarest to busiest so budget unused by rare keys flows to busier ones. It
+// also returns the overall rate, budget over total volume, capped at 1.
+func allocateRates(volume map[string]float64, budget float64) (map[string]float64, float64) {
+	total := 0.0
+	for _, v := range volume {
+		total += v
+	}
+	if total <= budget {
+		return nil, 1
+	}
+
+	keys := make([]string, 0, len(volume))
+	weightSum := 0.0
+	for k, v := range volume {
+		keys = append(keys, k)
+		weightSum += volumeWeight(v)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if volume[keys[i]] != volume[keys[j]] {
+			return volume[keys[i]] < volume[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	rates := make(map[string]float64, len(keys))
+	remaining := budget
+	for _, k := range keys {
+		count := volume[k]
+		weight := volumeWeight(count)
+		share := remaining * weight / weightSum
+		weightSum -= weight
+		if share >= count {
+			rates[k] = 1
+			remaining -= count
+			continue
+		}
+		rates[k] = share / count
+		remaining -= share
+	}
+	return rates, budget / total
+}
+
+// volumeWeight is a key's share weight. Volumes below 1, left by a decayed
+// previous window, weigh the same as 1.
+func volumeWeight(v float64) float64 {
+	return math.Log10(math.Max(v, 1)) + 1
+}
+
+func defaultDynamicKey(in SampleInput) string {
+	route := in.Path
+	if in.Event != nil {
+		if v, ok := in.Event.lookup("http.route"); ok {
+			if r, ok := v.(string); ok && r != "" {
+				route = r
+			}
+		}
+	}
+	return route + " " + strconv.Itoa(in.StatusCode/100) + "xx"
+}
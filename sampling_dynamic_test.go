@@ -0,0 +1,155 @@
+package hc
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAllocateRatesFavorsRareKeys(t *testing.T) {
+	rates, overall := allocateRates(map[string]float64{"hot": 10000, "rare": 10}, 100)
+
+	if rates["rare"] != 1 {
+		t.Fatalf("rare rate = %v, want 1", rates["rare"])
+	}
+	if math.Abs(rates["hot"]-0.009) > 1e-9 {
+		t.Fatalf("hot rate = %v, want 0.009", rates["hot"])
+	}
+	expected := 10000*rates["hot"] + 10*rates["rare"]
+	if math.Abs(expected-100) > 1e-6 {
+		t.Fatalf("expected output = %v, want budget 100", expected)
+	}
+	if math.Abs(overall-100.0/10010) > 1e-9 {
+		t.Fatalf("overall rate = %v, want %v", overall, 100.0/10010)
+	}
+}
+
+func TestAllocateRatesUnderBudgetKeepsEverything(t *testing.T) {
+	if rates, overall := allocateRates(map[string]float64{"a": 5, "b": 5}, 100); rates != nil || overall != 1 {
+		t.Fatalf("expected no rate limits under budget, got %v, %v", rates, overall)
+	}
+}
+
+func TestDynamicSamplerAdaptsAfterWindow(t *testing.T) {
+	now := time.Unix(0, 0)
+	d := newDynamicSampler(DynamicSamplerOptions{
+		TargetEventsPerSecond: 1,
+		Window:                10 * time.Second,
+	}, func() time.Time { return now })
+
+	hot := SampleInput{Path: "/hot", StatusCode: 200}
+	rare := SampleInput{Path: "/rare", StatusCode: 200}
+
+	for range 1000 {
+		if !d.sample(hot) {
+			t.Fatal("expected first window to keep everything")
+		}
+	}
+	d.sample(rare)
+
+	now = now.Add(10 * time.Second)
+	keptHot := 0
+	for range 1000 {
+		if d.sample(hot) {
+			keptHot++
+		}
+	}
+	if keptHot == 0 || keptHot > 50 {
+		t.Fatalf("kept %d hot events, want a small sampled fraction", keptHot)
+	}
+
+	_, e := NewContext(context.Background())
+	rare.Event = e
+	if !d.sample(rare) {
+		t.Fatal("expected rare key to be kept")
+	}
+	if rate, ok := EventSampleRate(e); !ok || rate != 1 {
+		t.Fatalf("sample rate = %v, %v; want 1", rate, ok)
+	}
+}
+
+func TestDynamicSamplerRecordsEffectiveRate(t *testing.T) {
+	now := time.Unix(0, 0)
+	d := newDynamicSampler(DynamicSamplerOptions{
+		TargetEventsPerSecond: 1,
+		Window:                time.Second,
+		Key:                   func(SampleInput) string { return "all" },
+	}, func() time.Time { return now })
+
+	for range 100 {
+		d.sample(SampleInput{})
+	}
+	now = now.Add(time.Second)
+
+	for range 1000 {
+		_, e := NewContext(context.Background())
+		if d.sample(SampleInput{Event: e}) {
+			if got, _ := EventSampleRate(e); got != 0.01 {
+				t.Fatalf("sample rate = %v, want 0.01", got)
+			}
+			return
+		}
+	}
+	t.Fatal("expected at least one kept event")
+}
+
+func TestDynamicSamplerSlidesAcrossWindowBoundary(t *testing.T) {
+	now := time.Unix(0, 0)
+	d := newDynamicSampler(DynamicSamplerOptions{
+		TargetEventsPerSecond: 1,
+		Window:                10 * time.Second,
+	}, func() time.Time { return now })
+
+	for range 1000 {
+		d.rateFor("hot")
+	}
+
+	// Halfway into the next window, half of the previous one still counts.
+	now = now.Add(15 * time.Second)
+	if got := d.rateFor("hot"); math.Abs(got-0.02) > 1e-9 {
+		t.Fatalf("hot rate = %v, want 0.02", got)
+	}
+	if got := d.rateFor("new"); math.Abs(got-0.02) > 1e-9 {
+		t.Fatalf("new key rate = %v, want the overall rate 0.02", got)
+	}
+
+	// Rates follow the decay within the window, not just at its boundary:
+	// hot now counts 100+1 and shares the budget of 10 with new's 1 event.
+	now = now.Add(4 * time.Second)
+	if got := d.rateFor("hot"); math.Abs(got-9.0/101) > 1e-9 {
+		t.Fatalf("hot rate = %v, want %v", got, 9.0/101)
+	}
+
+	// Windows without traffic drop the old counts.
+	now = now.Add(20 * time.Second)
+	if got := d.rateFor("hot"); got != 1 {
+		t.Fatalf("hot rate after idle windows = %v, want 1", got)
+	}
+}
+
+func TestDynamicSamplerBoundsKeys(t *testing.T) {
+	d := newDynamicSampler(DynamicSamplerOptions{
+		MaxKeys: 2,
+		Key:     func(in SampleInput) string { return in.Path },
+	}, time.Now)
+
+	for _, p := range []string{"/a", "/b", "/c", "/d"} {
+		d.sample(SampleInput{Path: p})
+	}
+	if len(d.counts) != 3 || d.counts[dynamicOverflowKey] != 2 {
+		t.Fatalf("unexpected counts: %v", d.counts)
+	}
+}
+
+func TestDefaultDynamicKeyUsesRouteAndStatusClass(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	SetRoute(ctx, "/orders/{id}")
+
+	if got := defaultDynamicKey(SampleInput{Path: "/orders/1", StatusCode: 404, Event: e}); got != "/orders/{id} 4xx" {
+		t.Fatalf("key = %q", got)
+	}
+	if got := defaultDynamicKey(SampleInput{Path: "/health", StatusCode: 200}); got != "/health 2xx" {
+		t.Fatalf("key = %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+package hc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// SampleKey extracts the key used by HashSampler.
+// It returns false when no key is available for the request.
+type SampleKey func(SampleInput) (string, bool)
+
+// KeyFromField returns a SampleKey reading the named event field.
+// Non-string values are formatted with fmt.Sprint.
+func KeyFromField(name string) SampleKey {
+	return func(in SampleInput) (string, bool) {
+		if in.Event == nil {
+			return "", false
+		}
+		v, ok := in.Event.lookup(name)
+		if !ok || v == nil {
+			return "", false
+		}
+		if s, ok := v.(string); ok {
+			return s, s != ""
+		}
+		return fmt.Sprint(v), true
+	}
+}
+
+// KeyFromHeader returns a SampleKey reading the named request header.
+func KeyFromHeader(name string) SampleKey {
+	return func(in SampleInput) (string, bool) {
+		if in.Header == nil {
+			return "", false
+		}
+		v := in.Header(name)
+		return v, v != ""
+	}
+}
+
+// KeyFromTraceID returns a SampleKey using the W3C trace ID.
+//
+// The trace_id event field is used when present; otherwise the trace ID is
+// parsed from the traceparent header.
+func KeyFromTraceID() SampleKey {
+	field := KeyFromField("trace_id")
+	return func(in SampleInput) (string, bool) {
+		if id, ok := field(in); ok {
+			return strings.ToLower(id), true
+		}
+		if in.Header == nil {
+			return "", false
+		}
+		return traceIDFromTraceparent(in.Header("traceparent"))
+	}
+}
+
+// HashSampler returns a deterministic sampler keeping rate in [0,1] of keys.
+//
+// The same key always yields the same decision for a given rate, so every
+// service sampling on a shared key (such as the trace ID) keeps or drops the
+// same requests. Keys are hashed with 64-bit FNV-1a followed by the SplitMix64
+// finalizer. Requests without a key fall back to RateSampler(rate).
+func HashSampler(rate float64, key SampleKey) Sampler {
+	switch {
+	case rate <= 0:
+		return NeverSampler()
+	case rate >= 1:
+		return AlwaysSampler()
+	}
+	fallback := RateSampler(rate)
+	if key == nil {
+		return fallback
+	}
+	threshold := uint64(math.Ldexp(rate, 64))
+	return func(in SampleInput) bool {
+		k, ok := key(in)
+		if !ok {
+			return fallback(in)
+		}
+		if hashSampleKey(k) >= threshold {
+			return false
+		}
+		reportSampleRate(in.Event, rate)
+		return true
+	}
+}
+
+func hashSampleKey(key string) uint64 {
+	x := uint64(14695981039346656037)
+	for i := 0; i < len(key); i++ {
+		x ^= uint64(key[i])
+		x *= 1099511628211
+	}
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// traceIDFromTraceparent extracts the trace ID from a W3C traceparent header
+// of the form version-traceid-parentid-flags.
+func traceIDFromTraceparent(header string) (string, bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 {
+		return "", false
+	}
+	id := strings.ToLower(parts[1])
+	raw, err := hex.DecodeString(id)
+	if err != nil {
+		return "", false
+	}
+	for _, b := range raw {
+		if b != 0 {
+			return id, true
+		}
+	}
+	return "", false
+}
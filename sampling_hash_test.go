@@ -0,0 +1,88 @@
+package hc
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+func TestHashSamplerIsDeterministic(t *testing.T) {
+	a := HashSampler(0.3, KeyFromHeader("X-User-ID"))
+	b := HashSampler(0.3, KeyFromHeader("X-User-ID"))
+
+	kept := 0
+	for i := range 2000 {
+		id := "user-" + strconv.Itoa(i)
+		in := SampleInput{Header: headerFunc(map[string]string{"X-User-ID": id})}
+		got := a(in)
+		if got != b(in) || got != a(in) {
+			t.Fatalf("expected stable decision for %s", id)
+		}
+		if got {
+			kept++
+		}
+	}
+	if kept < 500 || kept > 700 {
+		t.Fatalf("kept %d of 2000 keys, want about 600", kept)
+	}
+}
+
+func TestHashSamplerRateBoundsAreMonotonic(t *testing.T) {
+	low := HashSampler(0.1, KeyFromHeader("X-Key"))
+	high := HashSampler(0.5, KeyFromHeader("X-Key"))
+	for i := range 500 {
+		in := SampleInput{Header: headerFunc(map[string]string{"X-Key": strconv.Itoa(i)})}
+		if low(in) && !high(in) {
+			t.Fatalf("key %d kept at 10%% but dropped at 50%%", i)
+		}
+	}
+	if HashSampler(0, KeyFromHeader("X-Key"))(SampleInput{}) {
+		t.Fatal("rate 0 should always drop")
+	}
+	if !HashSampler(1, KeyFromHeader("X-Key"))(SampleInput{}) {
+		t.Fatal("rate 1 should always keep")
+	}
+}
+
+func TestKeyFromField(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	Add(ctx, "user_id", 42)
+
+	key, ok := KeyFromField("user_id")(SampleInput{Event: e})
+	if !ok || key != "42" {
+		t.Fatalf("key = %q (ok=%v), want 42", key, ok)
+	}
+	if _, ok := KeyFromField("missing")(SampleInput{Event: e}); ok {
+		t.Fatal("expected missing field to have no key")
+	}
+	if _, ok := KeyFromField("user_id")(SampleInput{}); ok {
+		t.Fatal("expected nil event to have no key")
+	}
+}
+
+func TestKeyFromTraceID(t *testing.T) {
+	const traceparent = "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"
+	key := KeyFromTraceID()
+
+	id, ok := key(SampleInput{Header: headerFunc(map[string]string{"traceparent": traceparent})})
+	if !ok || id != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("trace id = %q (ok=%v)", id, ok)
+	}
+
+	ctx, e := NewContext(context.Background())
+	Add(ctx, "trace_id", "0af7651916cd43dd8448eb211c80319c")
+	id, ok = key(SampleInput{Event: e, Header: headerFunc(map[string]string{"traceparent": traceparent})})
+	if !ok || id != "0af7651916cd43dd8448eb211c80319c" {
+		t.Fatalf("expected trace_id field to win, got %q", id)
+	}
+
+	for _, bad := range []string{"", "00-xyz-00f067aa0ba902b7-01", "00-00000000000000000000000000000000-00f067aa0ba902b7-01"} {
+		if _, ok := traceIDFromTraceparent(bad); ok {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func headerFunc(values map[string]string) func(string) string {
+	return func(name string) string { return values[name] }
+}
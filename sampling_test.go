@@ -1,6 +1,7 @@
 package hc
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -35,6 +36,22 @@ func TestKeepSlowerThanNegativeDuration(t *testing.T) {
 	}
 }
 
+func TestKeepLargerThan(t *testing.T) {
+	s := ChainSampler(NeverSampler(), KeepLargerThan(1024))
+	if s(SampleInput{RequestSize: 1024, ResponseSize: 10}) {
+		t.Fatal("expected sizes at threshold to fall through")
+	}
+	if !s(SampleInput{RequestSize: 1025}) {
+		t.Fatal("expected large request to be kept")
+	}
+	if !s(SampleInput{ResponseSize: 4096}) {
+		t.Fatal("expected large response to be kept")
+	}
+	if !ChainSampler(NeverSampler(), KeepLargerThan(-5))(SampleInput{ResponseSize: 1}) {
+		t.Fatal("expected negative threshold to be treated as zero")
+	}
+}
+
 func TestRateSamplerBounds(t *testing.T) {
 	if RateSampler(0)(SampleInput{}) {
 		t.Fatal("rate 0 should always drop")
@@ -50,6 +67,53 @@ func TestRateSamplerBounds(t *testing.T) {
 	}
 }
 
+func TestRateSamplerReportsRate(t *testing.T) {
+	s := RateSampler(0.5)
+	for range 100 {
+		_, e := NewContext(context.Background())
+		if !s(SampleInput{Event: e}) {
+			if _, ok := EventSampleRate(e); ok {
+				t.Fatal("dropped event should not report a rate")
+			}
+			continue
+		}
+		if rate, ok := EventSampleRate(e); !ok || rate != 0.5 {
+			t.Fatalf("sample rate = %v, %v; want 0.5", rate, ok)
+		}
+	}
+}
+
+func TestFromResultSampler(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   SampleResult
+		wantKeep bool
+		wantRate float64
+	}{
+		{name: "kept", result: SampleResult{Keep: true, Rate: 0.1}, wantKeep: true, wantRate: 0.1},
+		{name: "dropped", result: SampleResult{Keep: false, Rate: 0.1}},
+		{name: "kept without rate", result: SampleResult{Keep: true}, wantKeep: true, wantRate: 1},
+		{name: "kept with rate above one", result: SampleResult{Keep: true, Rate: 3}, wantKeep: true, wantRate: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, e := NewContext(context.Background())
+			s := FromResultSampler(func(SampleInput) SampleResult { return tt.result })
+			if got := s(SampleInput{Event: e}); got != tt.wantKeep {
+				t.Fatalf("keep = %v, want %v", got, tt.wantKeep)
+			}
+			if rate, _ := EventSampleRate(e); rate != tt.wantRate {
+				t.Fatalf("sample rate = %v, want %v", rate, tt.wantRate)
+			}
+		})
+	}
+
+	if FromResultSampler(nil)(SampleInput{}) {
+		t.Fatal("nil result sampler should drop")
+	}
+}
+
 func ExampleChainSampler() {
 	sampler := ChainSampler(
 		RateSampler(0),
@@ -13,10 +13,15 @@ happycontext-vX.Y.Z tags:
   adapter/slog/vX.Y.Z
   adapter/zap/vX.Y.Z
   adapter/zerolog/vX.Y.Z
+  integration/chi/vX.Y.Z
   integration/echo/vX.Y.Z
   integration/fiber/vX.Y.Z
   integration/fiberv3/vX.Y.Z
   integration/gin/vX.Y.Z
+  integration/gorillamux/vX.Y.Z
+  integration/grpc/vX.Y.Z
+  integration/httprouter/vX.Y.Z
+  integration/otel/vX.Y.Z
   integration/std/vX.Y.Z
 
 Examples:
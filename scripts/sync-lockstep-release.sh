@@ -31,7 +31,7 @@ update_root_requirement() {
     return
   fi
 
-  perl -0pi -e "s#github\\.com/happytoolin/happycontext v\\d+\\.\\d+\\.\\d+#github.com/happytoolin/happycontext v${version}#g" "$file"
+  perl -0pi -e "s#github\\.com/happytoolin/happycontext((?:/integration/std)?) v\\d+\\.\\d+\\.\\d+#github.com/happytoolin/happycontext\$1 v${version}#g" "$file"
 }
 
 while IFS= read -r modfile; do
@@ -41,10 +41,15 @@ done < <(
     adapter/slog/go.mod \
     adapter/zap/go.mod \
     adapter/zerolog/go.mod \
+    integration/chi/go.mod \
     integration/echo/go.mod \
     integration/fiber/go.mod \
     integration/fiberv3/go.mod \
     integration/gin/go.mod \
+    integration/gorillamux/go.mod \
+    integration/grpc/go.mod \
+    integration/httprouter/go.mod \
+    integration/otel/go.mod \
     integration/std/go.mod \
     bench/go.mod \
     cmd/examples/go.mod
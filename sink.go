@@ -18,3 +18,13 @@ const (
 type Sink interface {
 	Write(level Level, message string, fields map[string]any)
 }
+
+// FieldSink is a Sink that can also receive fields as a typed list,
+// avoiding the map built for Write.
+//
+// Integrations call WriteFields when no Redactor or OnFinalize hook needs
+// the map form. fields is only valid for the duration of the call.
+type FieldSink interface {
+	Sink
+	WriteFields(level Level, message string, fields []Field)
+}
@@ -0,0 +1,85 @@
+package hc
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+const timingField = "timing"
+
+func noopStop() {}
+
+// StartTimer starts timing the named section of a request and returns a
+// function that stops it:
+//
+//	stop := hc.StartTimer(ctx, "db")
+//	defer stop()
+//
+// Each stopped timer adds its duration and one call to name in the event's
+// timing field, which holds <name>_ms and <name>_count per name. Timers may
+// run concurrently and repeat; stop records only on its first call.
+func StartTimer(ctx context.Context, name string) func() {
+	e := FromContext(ctx)
+	if e == nil {
+		return noopStop
+	}
+	start := time.Now()
+	var stopped atomic.Bool
+	return func() {
+		if stopped.Swap(true) {
+			return
+		}
+		e.recordTiming(name, time.Since(start))
+	}
+}
+
+// RecordTiming adds an already measured duration for name to the timing field
+// of the event stored in ctx, counting it as one call.
+func RecordTiming(ctx context.Context, name string, d time.Duration) bool {
+	e := FromContext(ctx)
+	if e == nil {
+		return false
+	}
+	e.recordTiming(name, d)
+	return true
+}
+
+func (e *Event) recordTiming(name string, d time.Duration) {
+	updateAggregate(e, timingField, newTimings, func(t *timings) {
+		t.record(name, d)
+	})
+}
+
+// timings accumulates durations and call counts per section name.
+type timings struct {
+	sections map[string]*timingSection
+}
+
+type timingSection struct {
+	count int
+	total time.Duration
+}
+
+func newTimings() *timings {
+	return &timings{sections: make(map[string]*timingSection, 4)}
+}
+
+func (t *timings) record(name string, d time.Duration) {
+	s := t.sections[name]
+	if s == nil {
+		s = &timingSection{}
+		t.sections[name] = s
+	}
+	s.count++
+	s.total += d
+}
+
+func (t *timings) fieldValue() any {
+	out := make(map[string]any, 2*len(t.sections))
+	for name, s := range t.sections {
+		out[name+"_ms"] = durationMillis(s.total)
+		out[name+"_count"] = s.count
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+package hc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRecordTimingAccumulatesPerName(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	RecordTiming(ctx, "db", 3*time.Millisecond)
+	RecordTiming(ctx, "db", 2*time.Millisecond)
+	RecordTiming(ctx, "render", 500*time.Microsecond)
+
+	timing, ok := EventFields(e)["timing"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected timing map, got %#v", EventFields(e)["timing"])
+	}
+	if timing["db_ms"] != 5.0 || timing["db_count"] != 2 {
+		t.Fatalf("unexpected db timing: %#v", timing)
+	}
+	if timing["render_ms"] != 0.5 || timing["render_count"] != 1 {
+		t.Fatalf("unexpected render timing: %#v", timing)
+	}
+}
+
+func TestStartTimerStopsOnce(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	stop := StartTimer(ctx, "db")
+	time.Sleep(time.Millisecond)
+	stop()
+	stop()
+
+	timing := EventFields(e)["timing"].(map[string]any)
+	if timing["db_count"] != 1 {
+		t.Fatalf("expected one db call, got %#v", timing)
+	}
+	if ms, _ := timing["db_ms"].(float64); ms < 1 {
+		t.Fatalf("expected at least 1ms, got %#v", timing["db_ms"])
+	}
+}
+
+func TestStartTimerConcurrent(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				StartTimer(ctx, "cache")()
+			}
+		}()
+	}
+	wg.Wait()
+
+	timing := EventFields(e)["timing"].(map[string]any)
+	if timing["cache_count"] != 400 {
+		t.Fatalf("expected 400 cache calls, got %#v", timing["cache_count"])
+	}
+}
+
+func TestTimersNoopWithoutEvent(t *testing.T) {
+	ctx := context.Background()
+	StartTimer(ctx, "db")()
+	if RecordTiming(ctx, "db", time.Second) {
+		t.Fatal("expected RecordTiming without event to report false")
+	}
+}
+
+func TestTimingSnapshotIsStable(t *testing.T) {
+	ctx, e := NewContext(context.Background())
+	RecordTiming(ctx, "db", time.Millisecond)
+	before := EventFields(e)["timing"].(map[string]any)
+	RecordTiming(ctx, "db", time.Millisecond)
+
+	if before["db_count"] != 1 {
+		t.Fatalf("snapshot changed after later record: %#v", before)
+	}
+}